@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -79,9 +80,29 @@ func ControllerProgressingConditionName(controllerName string) string {
 }
 
 func UpdateControllerConditions(ctx context.Context, operatorClient v1helpers.OperatorClient, allConditionNames sets.String, updatedConditions []operatorv1.OperatorCondition) error {
+	return UpdateStagedControllerConditions(ctx, operatorClient, allConditionNames, sets.NewString(), updatedConditions)
+}
+
+// UpdateStagedControllerConditions works like UpdateControllerConditions for controllers
+// that evaluate their conditions in stages. The skippedConditionNames were not evaluated
+// in this sync because an earlier stage failed, instead of being reset to their healthy
+// defaults they keep their last status and are marked as possibly stale.
+func UpdateStagedControllerConditions(ctx context.Context, operatorClient v1helpers.OperatorClient, allConditionNames, skippedConditionNames sets.String, updatedConditions []operatorv1.OperatorCondition) error {
 	updateConditionFuncs := []v1helpers.UpdateStatusFunc{}
 
+	failedConditions := []string{}
+	for _, condition := range updatedConditions {
+		if condition.Status == operatorv1.ConditionTrue && strings.HasSuffix(condition.Type, "Degraded") {
+			failedConditions = append(failedConditions, condition.Type)
+		}
+	}
+
 	for _, conditionType := range allConditionNames.List() {
+		if skippedConditionNames.Has(conditionType) && v1helpers.FindOperatorCondition(updatedConditions, conditionType) == nil {
+			updateConditionFuncs = append(updateConditionFuncs, markConditionStale(conditionType, failedConditions))
+			continue
+		}
+
 		// clean up existing updatedConditions
 		newCondition := operatorv1.OperatorCondition{
 			Type:   conditionType,
@@ -103,3 +124,42 @@ func UpdateControllerConditions(ctx context.Context, operatorClient v1helpers.Op
 
 	return nil
 }
+
+const staleConditionMessagePrefix = "possibly stale"
+
+// markConditionStale keeps the last status of a condition that was not evaluated and
+// notes which conditions kept it from being refreshed in its message
+func markConditionStale(conditionType string, failedConditions []string) v1helpers.UpdateStatusFunc {
+	return func(status *operatorv1.OperatorStatus) error {
+		existing := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+		if existing == nil {
+			// never evaluated, there's nothing that could look healthy
+			return nil
+		}
+
+		condition := *existing
+		condition.Message = staleConditionMessage(existing.Message, failedConditions)
+		v1helpers.SetOperatorCondition(&status.Conditions, condition)
+		return nil
+	}
+}
+
+// staleConditionMessage prefixes the last message of a condition with the stale note,
+// replacing the note of an earlier sync so that it does not pile up
+func staleConditionMessage(lastMessage string, failedConditions []string) string {
+	if strings.HasPrefix(lastMessage, staleConditionMessagePrefix) {
+		if _, rest, found := strings.Cut(lastMessage, ": "); found {
+			lastMessage = rest
+		} else {
+			lastMessage = ""
+		}
+	}
+
+	note := staleConditionMessagePrefix
+	if len(failedConditions) > 0 {
+		note += fmt.Sprintf(", not refreshed because %s failed", strings.Join(failedConditions, ", "))
+	} else {
+		note += ", not refreshed because an earlier check failed"
+	}
+	return note + ": " + lastMessage
+}
@@ -1,12 +1,15 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const controllerName = "TestController"
@@ -101,3 +104,38 @@ func TestControllerProgressingErrorIsDegraded(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateStagedControllerConditions(t *testing.T) {
+	allConditions := sets.NewString("FirstStageDegraded", "SecondStageDegraded")
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: "FirstStageDegraded", Status: operatorv1.ConditionFalse},
+			{Type: "SecondStageDegraded", Status: operatorv1.ConditionFalse, Message: "all good"},
+		},
+	}, nil)
+
+	firstStageFailure := []operatorv1.OperatorCondition{{Type: "FirstStageDegraded", Status: operatorv1.ConditionTrue, Reason: "Broken"}}
+	expectedMessage := "possibly stale, not refreshed because FirstStageDegraded failed: all good"
+
+	// the stale note must not pile up over several syncs
+	for i := 0; i < 2; i++ {
+		if err := UpdateStagedControllerConditions(context.Background(), operatorClient, allConditions, sets.NewString("SecondStageDegraded"), firstStageFailure); err != nil {
+			t.Fatal(err)
+		}
+
+		_, status, _, _ := operatorClient.GetOperatorState()
+		secondStage := v1helpers.FindOperatorCondition(status.Conditions, "SecondStageDegraded")
+		if secondStage.Status != operatorv1.ConditionFalse || secondStage.Message != expectedMessage {
+			t.Fatalf("expected the second stage condition to keep its status and be marked stale, got %#v", secondStage)
+		}
+	}
+
+	// once refreshed, the note is gone
+	if err := UpdateControllerConditions(context.Background(), operatorClient, allConditions, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, _ := operatorClient.GetOperatorState()
+	if secondStage := v1helpers.FindOperatorCondition(status.Conditions, "SecondStageDegraded"); len(secondStage.Message) != 0 {
+		t.Errorf("expected the refreshed condition to lose the stale note, got %q", secondStage.Message)
+	}
+}
@@ -3,12 +3,15 @@ package common
 import (
 	"bytes"
 	"encoding/json"
+	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/klog/v2"
 
+	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
 )
 
@@ -31,6 +34,28 @@ func UnstructuredConfigFrom(observedBytes []byte, prefix ...string) ([]byte, err
 	return json.Marshal(actualConfig)
 }
 
+// DecodeUnsupportedConfigOverrides decodes the operator's unsupportedConfigOverrides
+// into the given object, it is left untouched if there are no overrides
+func DecodeUnsupportedConfigOverrides(spec *operatorv1.OperatorSpec, into interface{}) error {
+	if spec.UnsupportedConfigOverrides.Raw == nil {
+		return nil
+	}
+
+	configJson, err := kyaml.ToJSON(spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		klog.Warning(err)
+		// maybe it's just json
+		configJson = spec.UnsupportedConfigOverrides.Raw
+	}
+
+	if err := json.NewDecoder(bytes.NewBuffer(configJson)).Decode(into); err != nil {
+		klog.V(4).Infof("decode of unsupported config failed with error: %v", err)
+		return err
+	}
+
+	return nil
+}
+
 // TODO: this should be in library-go
 func NamesFilter(names ...string) factory.EventFilterFunc {
 	nameSet := sets.NewString(names...)
@@ -42,3 +67,27 @@ func NamesFilter(names ...string) factory.EventFilterFunc {
 		return nameSet.Has(metaObj.GetObjectMeta().GetName())
 	}
 }
+
+// SpecChangedFilter passes the events of an object only once its generation changed, i.e.
+// its spec was updated. A controller that writes the status of the operator config uses it
+// so that its own status updates do not trigger its syncs again, the other changes are
+// picked up by the resync.
+func SpecChangedFilter() factory.EventFilterFunc {
+	var lock sync.Mutex
+	lastGeneration := int64(-1)
+	return func(obj interface{}) bool {
+		metaObj, ok := obj.(metav1.ObjectMetaAccessor)
+		if !ok {
+			// e.g. a tombstone
+			return true
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		if generation := metaObj.GetObjectMeta().GetGeneration(); generation != lastGeneration {
+			lastGeneration = generation
+			return true
+		}
+		return false
+	}
+}
@@ -0,0 +1,23 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestSpecChangedFilter(t *testing.T) {
+	withGeneration := func(generation int64) *operatorv1.Authentication {
+		return &operatorv1.Authentication{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Generation: generation}}
+	}
+
+	filter := SpecChangedFilter()
+	require.True(t, filter(withGeneration(1)), "the first event is passed")
+	require.False(t, filter(withGeneration(1)), "a status update keeps the generation")
+	require.True(t, filter(withGeneration(2)), "a spec update bumps the generation")
+	require.False(t, filter(withGeneration(2)))
+}
@@ -2,9 +2,11 @@ package common
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -22,17 +24,41 @@ func GetIngressConfig(ingressLister configv1lister.IngressLister, conditionPrefi
 			Message: fmt.Sprintf("Unable to get cluster ingress config: %v", err),
 		}}
 	}
-	if len(ingress.Spec.Domain) == 0 {
+	domain, err := NormalizeIngressDomain(ingress.Spec.Domain)
+	if err != nil {
 		return nil, []operatorv1.OperatorCondition{{
 			Type:    conditionPrefix + "Degraded",
 			Status:  operatorv1.ConditionTrue,
 			Reason:  "Invalid",
-			Message: fmt.Sprintf("The ingress config domain cannot be empty"),
+			Message: fmt.Sprintf("The ingress config domain is invalid: %v", err),
 		}}
 	}
+
+	ingress = ingress.DeepCopy()
+	ingress.Spec.Domain = domain
 	return ingress, nil
 }
 
+// NormalizeIngressDomain returns the ingress domain in a form that can be used to build
+// host names from it - lowercased and stripped of leading wildcards and trailing dots.
+// An error is returned if the result is not a valid DNS subdomain.
+func NormalizeIngressDomain(domain string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(domain))
+	normalized = strings.TrimRight(normalized, ".")
+	for strings.HasPrefix(normalized, "*.") {
+		normalized = strings.TrimPrefix(normalized, "*.")
+	}
+
+	if len(normalized) == 0 {
+		return "", fmt.Errorf("the ingress config domain cannot be empty")
+	}
+	if errs := validation.IsDNS1123Subdomain(normalized); len(errs) > 0 {
+		return "", fmt.Errorf("the ingress config domain %q is not a valid DNS subdomain: %s", domain, strings.Join(errs, ", "))
+	}
+
+	return normalized, nil
+}
+
 // GetComponentRouteSpec searches the entries of the ingress.spec.componentRoutes array for a componentRoute with a matching namespace and name.
 // If a matching componentRoute is found a pointer to it is returned, otherwise nil is returned.
 func GetComponentRouteSpec(ingress *configv1.Ingress, namespace string, name string) *configv1.ComponentRouteSpec {
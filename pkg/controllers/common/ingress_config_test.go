@@ -207,3 +207,69 @@ func TestGetComponentRouteStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeIngressDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Keep a valid domain as is",
+			domain: "apps.example.com",
+			want:   "apps.example.com",
+		},
+		{
+			name:   "Lowercase the domain",
+			domain: "Apps.EXAMPLE.com",
+			want:   "apps.example.com",
+		},
+		{
+			name:   "Strip a trailing dot",
+			domain: "apps.example.com.",
+			want:   "apps.example.com",
+		},
+		{
+			name:   "Strip a leading wildcard",
+			domain: "*.apps.example.com",
+			want:   "apps.example.com",
+		},
+		{
+			name:   "Strip all of it at once",
+			domain: " *.*.Apps.Example.com.. ",
+			want:   "apps.example.com",
+		},
+		{
+			name:    "Fail on an empty domain",
+			domain:  "",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on a domain that is only a wildcard",
+			domain:  "*.",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on a wildcard in the middle of the domain",
+			domain:  "apps.*.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "Fail on invalid characters",
+			domain:  "apps_example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeIngressDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeIngressDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeIngressDomain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package common
+
+const (
+	// TargetNamespace is the namespace the oauth-server and its configuration live in
+	TargetNamespace = "openshift-authentication"
+	// OperatorNamespace is the namespace the operator itself runs in
+	OperatorNamespace = "openshift-authentication-operator"
+)
@@ -0,0 +1,28 @@
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// ReconciliationPausedAnnotation can be set on the operator config to stop the operator
+// from applying any changes to its operands, checks of their health keep running
+const ReconciliationPausedAnnotation = "authentication.operator.openshift.io/pause"
+
+// IsReconciliationPaused returns true if the operator config carries the pause annotation
+func IsReconciliationPaused(operatorConfigMeta metav1.Object) bool {
+	_, paused := operatorConfigMeta.GetAnnotations()[ReconciliationPausedAnnotation]
+	return paused
+}
+
+// IsOperatorReconciliationPaused returns true if the operator config retrieved by the
+// operator client carries the pause annotation
+func IsOperatorReconciliationPaused(operatorClient v1helpers.OperatorClient) (bool, error) {
+	operatorConfigMeta, err := operatorClient.GetObjectMeta()
+	if err != nil {
+		return false, err
+	}
+
+	return IsReconciliationPaused(operatorConfigMeta), nil
+}
@@ -2,6 +2,9 @@ package common
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -11,14 +14,14 @@ import (
 )
 
 func GetOAuthServerRoute(routeLister routev1lister.RouteLister, conditionPrefix string) (*routev1.Route, []operatorv1.OperatorCondition) {
-	route, err := routeLister.Routes("openshift-authentication").Get("oauth-openshift")
+	route, err := routeLister.Routes(TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil, []operatorv1.OperatorCondition{{
 				Type:    conditionPrefix + "Degraded",
 				Status:  operatorv1.ConditionTrue,
 				Reason:  "NotFound",
-				Message: "The OAuth server route 'openshift-authentication/oauth-openshift' was not found",
+				Message: fmt.Sprintf("The OAuth server route '%s/oauth-openshift' was not found", TargetNamespace),
 			}}
 		}
 
@@ -27,9 +30,56 @@ func GetOAuthServerRoute(routeLister routev1lister.RouteLister, conditionPrefix
 				Type:    conditionPrefix + "Degraded",
 				Status:  operatorv1.ConditionTrue,
 				Reason:  "GetFailed",
-				Message: fmt.Sprintf("Unable to get 'openshift-authentication/oauth-openshift' route: %v", err),
+				Message: fmt.Sprintf("Unable to get '%s/oauth-openshift' route: %v", TargetNamespace, err),
 			},
 		}
 	}
 	return route, nil
 }
+
+// DefaultRouteHealthPath is the oauth-server health endpoint the oauth route is probed at
+const DefaultRouteHealthPath = "/healthz"
+
+// DefaultRouteTimeout leaves enough time to the login flows that wait for
+// slow identity providers, e.g. LDAP, the router would give up after 30s
+const DefaultRouteTimeout = "1m"
+
+// routeTimeoutRegexp matches the timeouts the router accepts, a number with an optional unit
+var routeTimeoutRegexp = regexp.MustCompile(`^[1-9][0-9]*(us|ms|s|m|h|d)?$`)
+
+// RouteOverrides are the knobs of the oauth route that can be set in the
+// "oauthServerRoute" key of the operator's unsupportedConfigOverrides
+type RouteOverrides struct {
+	// HealthPath is the path the oauth route is probed at, for fronting proxies
+	// that expose the health endpoint elsewhere, defaults to "/healthz"
+	HealthPath string `json:"healthPath,omitempty"`
+	// Timeout is the router timeout of the oauth route, defaults to "1m"
+	Timeout string `json:"timeout,omitempty"`
+	// HostTemplate is the template of the default host of the oauth route, it may
+	// use the {cluster-id} and {ingress-domain} placeholders
+	HostTemplate string `json:"hostTemplate,omitempty"`
+}
+
+func GetRouteOverrides(spec *operatorv1.OperatorSpec) (*RouteOverrides, error) {
+	unsupportedConfig := struct {
+		OAuthServerRoute RouteOverrides `json:"oauthServerRoute"`
+	}{}
+	if err := DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode unsupportedConfigOverrides: %w", err)
+	}
+
+	overrides := &unsupportedConfig.OAuthServerRoute
+	if len(overrides.HealthPath) == 0 {
+		overrides.HealthPath = DefaultRouteHealthPath
+	} else if parsed, err := url.Parse(overrides.HealthPath); err != nil || overrides.HealthPath[0] != '/' || parsed.Path != overrides.HealthPath || path.Clean(overrides.HealthPath) != overrides.HealthPath {
+		return nil, fmt.Errorf("invalid oauthServerRoute.healthPath %q, must be a clean absolute URL path without a query or a fragment", overrides.HealthPath)
+	}
+
+	if len(overrides.Timeout) == 0 {
+		overrides.Timeout = DefaultRouteTimeout
+	} else if !routeTimeoutRegexp.MatchString(overrides.Timeout) {
+		return nil, fmt.Errorf("invalid oauthServerRoute.timeout %q, must be a positive number with an optional unit of us, ms, s, m, h or d", overrides.Timeout)
+	}
+
+	return overrides, nil
+}
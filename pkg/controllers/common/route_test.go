@@ -0,0 +1,91 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestGetRouteOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     string
+		want          *RouteOverrides
+		expectedError bool
+	}{
+		{
+			name: "default",
+			want: &RouteOverrides{HealthPath: DefaultRouteHealthPath, Timeout: DefaultRouteTimeout},
+		},
+		{
+			name:      "custom path",
+			overrides: `{"oauthServerRoute": {"healthPath": "/oauth/healthz"}}`,
+			want:      &RouteOverrides{HealthPath: "/oauth/healthz", Timeout: DefaultRouteTimeout},
+		},
+		{
+			name:          "relative path",
+			overrides:     `{"oauthServerRoute": {"healthPath": "healthz"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "query",
+			overrides:     `{"oauthServerRoute": {"healthPath": "/healthz?verbose"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "full URL",
+			overrides:     `{"oauthServerRoute": {"healthPath": "https://proxy.example.com/healthz"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unclean path",
+			overrides:     `{"oauthServerRoute": {"healthPath": "/oauth/../healthz"}}`,
+			expectedError: true,
+		},
+		{
+			name:      "custom timeout",
+			overrides: `{"oauthServerRoute": {"timeout": "5m"}}`,
+			want:      &RouteOverrides{HealthPath: DefaultRouteHealthPath, Timeout: "5m"},
+		},
+		{
+			name:      "seconds without unit",
+			overrides: "oauthServerRoute:\n  timeout: \"90\"\n",
+			want:      &RouteOverrides{HealthPath: DefaultRouteHealthPath, Timeout: "90"},
+		},
+		{
+			name:          "go duration",
+			overrides:     `{"oauthServerRoute": {"timeout": "1m30s"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "zero timeout",
+			overrides:     `{"oauthServerRoute": {"timeout": "0s"}}`,
+			expectedError: true,
+		},
+		{
+			name:      "host template",
+			overrides: `{"oauthServerRoute": {"hostTemplate": "oauth-{cluster-id}.{ingress-domain}"}}`,
+			want:      &RouteOverrides{HealthPath: DefaultRouteHealthPath, Timeout: DefaultRouteTimeout, HostTemplate: "oauth-{cluster-id}.{ingress-domain}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &operatorv1.OperatorSpec{}
+			if len(tt.overrides) > 0 {
+				spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tt.overrides)}
+			}
+
+			got, err := GetRouteOverrides(spec)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
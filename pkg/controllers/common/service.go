@@ -9,7 +9,7 @@ import (
 )
 
 func GetOAuthServerService(serviceLister v1.ServiceLister, conditionPrefix string) (*corev1.Service, []operatorv1.OperatorCondition) {
-	service, err := serviceLister.Services("openshift-authentication").Get("oauth-openshift")
+	service, err := serviceLister.Services(TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		return nil, []operatorv1.OperatorCondition{
 			{
@@ -0,0 +1,102 @@
+package configconsistency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// configConsistencyController reports the combinations of the cluster authentication
+// config, the cluster oauth config and the operator config that the operator cannot
+// reconcile and that would otherwise silently have no effect
+type configConsistencyController struct {
+	authLister     configv1listers.AuthenticationLister
+	oauthLister    configv1listers.OAuthLister
+	operatorClient v1helpers.OperatorClient
+}
+
+func NewConfigConsistencyController(
+	configInformers configinformers.SharedInformerFactory,
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &configConsistencyController{
+		authLister:     configInformers.Config().V1().Authentications().Lister(),
+		oauthLister:    configInformers.Config().V1().OAuths().Lister(),
+		operatorClient: operatorClient,
+	}
+
+	return factory.New().
+		WithInformers(
+			configInformers.Config().V1().Authentications().Informer(),
+			configInformers.Config().V1().OAuths().Informer(),
+			operatorClient.Informer(),
+		).
+		WithSync(c.sync).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("ConfigConsistencyController", eventRecorder.WithComponentSuffix("config-consistency-controller"))
+}
+
+func (c *configConsistencyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	authConfig, err := c.authLister.Get("cluster")
+	if err != nil {
+		return err
+	}
+
+	oauthConfig, err := c.oauthLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		oauthConfig = &configv1.OAuth{}
+	} else if err != nil {
+		return err
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(configConflictsCondition(authConfig, oauthConfig, operatorSpec)))
+	return err
+}
+
+func configConflictsCondition(authConfig *configv1.Authentication, oauthConfig *configv1.OAuth, operatorSpec *operatorv1.OperatorSpec) operatorv1.OperatorCondition {
+	conflicts := []string{}
+
+	integratedOAuth := authConfig.Spec.Type == configv1.AuthenticationTypeIntegratedOAuth || len(authConfig.Spec.Type) == 0
+	if !integratedOAuth && len(oauthConfig.Spec.IdentityProviders) > 0 {
+		conflicts = append(conflicts, fmt.Sprintf("authentications.config.openshift.io/cluster has type %q but oauths.config.openshift.io/cluster configures %d identity providers, users cannot log in through them", authConfig.Spec.Type, len(oauthConfig.Spec.IdentityProviders)))
+	}
+	if integratedOAuth && len(authConfig.Spec.OAuthMetadata.Name) > 0 {
+		conflicts = append(conflicts, fmt.Sprintf("authentications.config.openshift.io/cluster references the openshift-config/%s config map as oauthMetadata, the metadata of the integrated oauth-server are not published", authConfig.Spec.OAuthMetadata.Name))
+	}
+	if integratedOAuth && operatorSpec.ManagementState != operatorv1.Managed && len(operatorSpec.ManagementState) > 0 {
+		conflicts = append(conflicts, fmt.Sprintf("the operator is %s, changes to oauths.config.openshift.io/cluster are not applied to the integrated oauth-server", operatorSpec.ManagementState))
+	}
+
+	if len(conflicts) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   "AuthenticationConfigConflicts",
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    "AuthenticationConfigConflicts",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "ConflictingConfig",
+		Message: strings.Join(conflicts, "\n"),
+	}
+}
@@ -0,0 +1,84 @@
+package configconsistency
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestConfigConflictsCondition(t *testing.T) {
+	htpasswd := []configv1.IdentityProvider{{Name: "htpasswd"}}
+
+	tests := []struct {
+		name            string
+		authSpec        configv1.AuthenticationSpec
+		oauthSpec       configv1.OAuthSpec
+		managementState operatorv1.ManagementState
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage []string
+	}{
+		{
+			name:            "integrated oauth with identity providers",
+			authSpec:        configv1.AuthenticationSpec{Type: configv1.AuthenticationTypeIntegratedOAuth},
+			oauthSpec:       configv1.OAuthSpec{IdentityProviders: htpasswd},
+			managementState: operatorv1.Managed,
+			expectedStatus:  operatorv1.ConditionFalse,
+		},
+		{
+			name:            "type defaults to integrated oauth",
+			oauthSpec:       configv1.OAuthSpec{IdentityProviders: htpasswd},
+			managementState: operatorv1.Managed,
+			expectedStatus:  operatorv1.ConditionFalse,
+		},
+		{
+			name:            "no authentication with identity providers",
+			authSpec:        configv1.AuthenticationSpec{Type: configv1.AuthenticationTypeNone},
+			oauthSpec:       configv1.OAuthSpec{IdentityProviders: htpasswd},
+			managementState: operatorv1.Managed,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: []string{`type "None"`, "1 identity providers"},
+		},
+		{
+			name:            "no authentication without identity providers",
+			authSpec:        configv1.AuthenticationSpec{Type: configv1.AuthenticationTypeNone},
+			managementState: operatorv1.Managed,
+			expectedStatus:  operatorv1.ConditionFalse,
+		},
+		{
+			name: "user metadata with integrated oauth",
+			authSpec: configv1.AuthenticationSpec{
+				Type:          configv1.AuthenticationTypeIntegratedOAuth,
+				OAuthMetadata: configv1.ConfigMapNameReference{Name: "my-metadata"},
+			},
+			managementState: operatorv1.Managed,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: []string{"openshift-config/my-metadata"},
+		},
+		{
+			name:            "unmanaged operator",
+			authSpec:        configv1.AuthenticationSpec{Type: configv1.AuthenticationTypeIntegratedOAuth},
+			managementState: operatorv1.Unmanaged,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: []string{"the operator is Unmanaged"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := configConflictsCondition(
+				&configv1.Authentication{Spec: tt.authSpec},
+				&configv1.OAuth{Spec: tt.oauthSpec},
+				&operatorv1.OperatorSpec{ManagementState: tt.managementState},
+			)
+
+			require.Equal(t, "AuthenticationConfigConflicts", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			for _, expected := range tt.expectedMessage {
+				require.True(t, strings.Contains(condition.Message, expected), "expected %q in %q", expected, condition.Message)
+			}
+		})
+	}
+}
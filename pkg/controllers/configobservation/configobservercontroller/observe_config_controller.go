@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation/console"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation/infrastructure"
@@ -29,7 +30,7 @@ func NewConfigObserver(
 	eventRecorder events.Recorder,
 ) factory.Controller {
 	interestingNamespaces := []string{
-		"openshift-authentication",
+		common.TargetNamespace,
 		"openshift-config",
 		"openshift-config-managed",
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/openshift/library-go/pkg/operator/configobserver"
@@ -39,7 +40,11 @@ func ObserveConsoleURL(genericlisters configobserver.Listers, recorder events.Re
 	}
 
 	consoleConfig, err := listers.ConsoleLister.Get("cluster")
-	if err != nil {
+	if errors.IsNotFound(err) {
+		// the console operator did not create its config yet, the oauth-server keeps working
+		// without a console and the payload controller reports it as absent
+		return existingConfig, nil
+	} else if err != nil {
 		return existingConfig, append(errs, err)
 	}
 	observedAssetURL := consoleConfig.Status.ConsoleURL
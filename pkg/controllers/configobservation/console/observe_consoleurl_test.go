@@ -34,7 +34,6 @@ func TestObserveConsoleURL(t *testing.T) {
 			clusterVersion: &configv1.ClusterVersionStatus{Capabilities: configv1.ClusterVersionCapabilitiesStatus{EnabledCapabilities: []configv1.ClusterVersionCapability{configv1.ClusterVersionCapabilityConsole}}},
 			existingConfig: existingConfig,
 			expectedConfig: existingConfig,
-			expectedErrs:   []string{"\"cluster\" not found"},
 		},
 		{
 			name:           "NoConsoleConfigConsoleCapabilityDisabled",
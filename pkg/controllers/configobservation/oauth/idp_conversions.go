@@ -64,11 +64,13 @@ func convertIdentityProviders(
 	errs := []error{}
 
 	for i, idp := range defaultIDPMappingMethods(identityProviders) {
-		data, err := convertProviderConfigToIDPData(cmLister, secretsLister, &idp.IdentityProviderConfig, syncData, i)
+		references := IdentityProviderReferences(&idp.IdentityProviderConfig, i)
+		data, err := convertProviderConfigToIDPData(cmLister, secretsLister, &idp.IdentityProviderConfig, references, i)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to apply IDP %s config: %v", idp.Name, err))
 			continue
 		}
+		syncData.Merge(references)
 		converted = append(converted,
 			osinv1.IdentityProvider{
 				Name:            idp.Name,
@@ -115,7 +117,7 @@ func convertProviderConfigToIDPData(
 	cmLister corelistersv1.ConfigMapLister,
 	secretsLister corelistersv1.SecretLister,
 	providerConfig *configv1.IdentityProviderConfig,
-	syncData *datasync.ConfigSyncData,
+	references *datasync.ConfigSyncData,
 	i int,
 ) (*idpData, error) {
 	const missingProviderFmt string = "type %s was specified, but its configuration is missing"
@@ -132,10 +134,10 @@ func convertProviderConfigToIDPData(
 		data.provider = &osinv1.BasicAuthPasswordIdentityProvider{
 			RemoteConnectionInfo: configv1.RemoteConnectionInfo{
 				URL: basicAuthConfig.URL,
-				CA:  syncData.AddIDPConfigMap(i, basicAuthConfig.CA, "ca", corev1.ServiceAccountRootCAKey),
+				CA:  references.IDPPath(i, "ca"),
 				CertInfo: configv1.CertInfo{
-					CertFile: syncData.AddIDPSecret(i, basicAuthConfig.TLSClientCert, "tls-client-cert", corev1.TLSCertKey),
-					KeyFile:  syncData.AddIDPSecret(i, basicAuthConfig.TLSClientKey, "tls-client-key", corev1.TLSPrivateKeyKey),
+					CertFile: references.IDPPath(i, "tls-client-cert"),
+					KeyFile:  references.IDPPath(i, "tls-client-key"),
 				},
 			},
 		}
@@ -149,11 +151,11 @@ func convertProviderConfigToIDPData(
 
 		data.provider = &osinv1.GitHubIdentityProvider{
 			ClientID:      githubConfig.ClientID,
-			ClientSecret:  createFileStringSource(syncData.AddIDPSecret(i, githubConfig.ClientSecret, "client-secret", configv1.ClientSecretKey)),
+			ClientSecret:  createFileStringSource(references.IDPPath(i, "client-secret")),
 			Organizations: githubConfig.Organizations,
 			Teams:         githubConfig.Teams,
 			Hostname:      githubConfig.Hostname,
-			CA:            syncData.AddIDPConfigMap(i, githubConfig.CA, "ca", corev1.ServiceAccountRootCAKey),
+			CA:            references.IDPPath(i, "ca"),
 		}
 		data.challenge = false
 
@@ -164,10 +166,10 @@ func convertProviderConfigToIDPData(
 		}
 
 		data.provider = &osinv1.GitLabIdentityProvider{
-			CA:           syncData.AddIDPConfigMap(i, gitlabConfig.CA, "ca", corev1.ServiceAccountRootCAKey),
+			CA:           references.IDPPath(i, "ca"),
 			URL:          gitlabConfig.URL,
 			ClientID:     gitlabConfig.ClientID,
-			ClientSecret: createFileStringSource(syncData.AddIDPSecret(i, gitlabConfig.ClientSecret, "client-secret", configv1.ClientSecretKey)),
+			ClientSecret: createFileStringSource(references.IDPPath(i, "client-secret")),
 			Legacy:       new(bool), // we require OIDC for GitLab now
 		}
 		data.challenge = true
@@ -180,7 +182,7 @@ func convertProviderConfigToIDPData(
 
 		data.provider = &osinv1.GoogleIdentityProvider{
 			ClientID:     googleConfig.ClientID,
-			ClientSecret: createFileStringSource(syncData.AddIDPSecret(i, googleConfig.ClientSecret, "client-secret", configv1.ClientSecretKey)),
+			ClientSecret: createFileStringSource(references.IDPPath(i, "client-secret")),
 			HostedDomain: googleConfig.HostedDomain,
 		}
 		data.challenge = false
@@ -191,7 +193,7 @@ func convertProviderConfigToIDPData(
 		}
 
 		data.provider = &osinv1.HTPasswdPasswordIdentityProvider{
-			File: syncData.AddIDPSecret(i, providerConfig.HTPasswd.FileData, "file-data", configv1.HTPasswdDataKey),
+			File: references.IDPPath(i, "file-data"),
 		}
 		data.challenge = true
 
@@ -204,10 +206,10 @@ func convertProviderConfigToIDPData(
 		data.provider = &osinv1.KeystonePasswordIdentityProvider{
 			RemoteConnectionInfo: configv1.RemoteConnectionInfo{
 				URL: keystoneConfig.URL,
-				CA:  syncData.AddIDPConfigMap(i, keystoneConfig.CA, "ca", corev1.ServiceAccountRootCAKey),
+				CA:  references.IDPPath(i, "ca"),
 				CertInfo: configv1.CertInfo{
-					CertFile: syncData.AddIDPSecret(i, keystoneConfig.TLSClientCert, "tls-client-cert", corev1.TLSCertKey),
-					KeyFile:  syncData.AddIDPSecret(i, keystoneConfig.TLSClientKey, "tls-client-key", corev1.TLSPrivateKeyKey),
+					CertFile: references.IDPPath(i, "tls-client-cert"),
+					KeyFile:  references.IDPPath(i, "tls-client-key"),
 				},
 			},
 			DomainName:          keystoneConfig.DomainName,
@@ -224,9 +226,9 @@ func convertProviderConfigToIDPData(
 		data.provider = &osinv1.LDAPPasswordIdentityProvider{
 			URL:          ldapConfig.URL,
 			BindDN:       ldapConfig.BindDN,
-			BindPassword: createFileStringSource(syncData.AddIDPSecret(i, ldapConfig.BindPassword, "bind-password", configv1.BindPasswordKey)),
+			BindPassword: createFileStringSource(references.IDPPath(i, "bind-password")),
 			Insecure:     ldapConfig.Insecure,
-			CA:           syncData.AddIDPConfigMap(i, ldapConfig.CA, "ca", corev1.ServiceAccountRootCAKey),
+			CA:           references.IDPPath(i, "ca"),
 			Attributes: osinv1.LDAPAttributeMapping{
 				ID:                ldapConfig.Attributes.ID,
 				PreferredUsername: ldapConfig.Attributes.PreferredUsername,
@@ -253,9 +255,9 @@ func convertProviderConfigToIDPData(
 		}
 
 		data.provider = &osinv1.OpenIDIdentityProvider{
-			CA:                       syncData.AddIDPConfigMap(i, openIDConfig.CA, "ca", corev1.ServiceAccountRootCAKey),
+			CA:                       references.IDPPath(i, "ca"),
 			ClientID:                 openIDConfig.ClientID,
-			ClientSecret:             createFileStringSource(syncData.AddIDPSecret(i, openIDConfig.ClientSecret, "client-secret", configv1.ClientSecretKey)),
+			ClientSecret:             createFileStringSource(references.IDPPath(i, "client-secret")),
 			ExtraScopes:              openIDConfig.ExtraScopes,
 			ExtraAuthorizeParameters: openIDConfig.ExtraAuthorizeParameters,
 			URLs:                     *urls,
@@ -294,7 +296,7 @@ func convertProviderConfigToIDPData(
 		data.provider = &osinv1.RequestHeaderIdentityProvider{
 			LoginURL:                 requestHeaderConfig.LoginURL,
 			ChallengeURL:             requestHeaderConfig.ChallengeURL,
-			ClientCA:                 syncData.AddIDPConfigMap(i, requestHeaderConfig.ClientCA, "ca", corev1.ServiceAccountRootCAKey),
+			ClientCA:                 references.IDPPath(i, "ca"),
 			ClientCommonNames:        requestHeaderConfig.ClientCommonNames,
 			Headers:                  requestHeaderConfig.Headers,
 			PreferredUsernameHeaders: requestHeaderConfig.PreferredUsernameHeaders,
@@ -21,7 +21,6 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	osinv1 "github.com/openshift/api/osin/v1"
-	"github.com/openshift/cluster-authentication-operator/pkg/operator/datasync"
 	"github.com/openshift/library-go/pkg/crypto"
 )
 
@@ -188,8 +187,6 @@ func Test_convertProviderConfigToIDPData(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			syncData := datasync.NewConfigSyncData()
-
 			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
 			if tt.configMap != nil {
 				require.NoError(t, indexer.Add(tt.configMap))
@@ -209,7 +206,7 @@ func Test_convertProviderConfigToIDPData(t *testing.T) {
 				tt.providerConfig.OpenID.Issuer = server.URL
 			}
 
-			got, err := convertProviderConfigToIDPData(cmLister, secretLister, tt.providerConfig, syncData, 0)
+			got, err := convertProviderConfigToIDPData(cmLister, secretLister, tt.providerConfig, IdentityProviderReferences(tt.providerConfig, 0), 0)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("convertProviderConfigToIDPData() error = %v, wantErr %v", err, tt.wantErr)
 				return
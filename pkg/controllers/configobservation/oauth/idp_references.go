@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/operator/datasync"
+)
+
+// IdentityProviderReferences returns the secrets and config maps referenced by the
+// i-th identity provider in the form they are synchronized for the oauth-server, the
+// conversion reads the paths of the references from it. Unlike the full conversion,
+// it does not reach out to the identity provider.
+func IdentityProviderReferences(providerConfig *configv1.IdentityProviderConfig, i int) *datasync.ConfigSyncData {
+	syncData := datasync.NewConfigSyncData()
+
+	switch providerConfig.Type {
+	case configv1.IdentityProviderTypeBasicAuth:
+		if c := providerConfig.BasicAuth; c != nil {
+			syncData.AddIDPConfigMap(i, c.CA, "ca", corev1.ServiceAccountRootCAKey)
+			syncData.AddIDPSecret(i, c.TLSClientCert, "tls-client-cert", corev1.TLSCertKey)
+			syncData.AddIDPSecret(i, c.TLSClientKey, "tls-client-key", corev1.TLSPrivateKeyKey)
+		}
+	case configv1.IdentityProviderTypeGitHub:
+		if c := providerConfig.GitHub; c != nil {
+			syncData.AddIDPSecret(i, c.ClientSecret, "client-secret", configv1.ClientSecretKey)
+			syncData.AddIDPConfigMap(i, c.CA, "ca", corev1.ServiceAccountRootCAKey)
+		}
+	case configv1.IdentityProviderTypeGitLab:
+		if c := providerConfig.GitLab; c != nil {
+			syncData.AddIDPConfigMap(i, c.CA, "ca", corev1.ServiceAccountRootCAKey)
+			syncData.AddIDPSecret(i, c.ClientSecret, "client-secret", configv1.ClientSecretKey)
+		}
+	case configv1.IdentityProviderTypeGoogle:
+		if c := providerConfig.Google; c != nil {
+			syncData.AddIDPSecret(i, c.ClientSecret, "client-secret", configv1.ClientSecretKey)
+		}
+	case configv1.IdentityProviderTypeHTPasswd:
+		if c := providerConfig.HTPasswd; c != nil {
+			syncData.AddIDPSecret(i, c.FileData, "file-data", configv1.HTPasswdDataKey)
+		}
+	case configv1.IdentityProviderTypeKeystone:
+		if c := providerConfig.Keystone; c != nil {
+			syncData.AddIDPConfigMap(i, c.CA, "ca", corev1.ServiceAccountRootCAKey)
+			syncData.AddIDPSecret(i, c.TLSClientCert, "tls-client-cert", corev1.TLSCertKey)
+			syncData.AddIDPSecret(i, c.TLSClientKey, "tls-client-key", corev1.TLSPrivateKeyKey)
+		}
+	case configv1.IdentityProviderTypeLDAP:
+		if c := providerConfig.LDAP; c != nil {
+			syncData.AddIDPSecret(i, c.BindPassword, "bind-password", configv1.BindPasswordKey)
+			syncData.AddIDPConfigMap(i, c.CA, "ca", corev1.ServiceAccountRootCAKey)
+		}
+	case configv1.IdentityProviderTypeOpenID:
+		if c := providerConfig.OpenID; c != nil {
+			syncData.AddIDPConfigMap(i, c.CA, "ca", corev1.ServiceAccountRootCAKey)
+			syncData.AddIDPSecret(i, c.ClientSecret, "client-secret", configv1.ClientSecretKey)
+		}
+	case configv1.IdentityProviderTypeRequestHeader:
+		if c := providerConfig.RequestHeader; c != nil {
+			syncData.AddIDPConfigMap(i, c.ClientCA, "ca", corev1.ServiceAccountRootCAKey)
+		}
+	}
+
+	return syncData
+}
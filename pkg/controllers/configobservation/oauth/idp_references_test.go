@@ -0,0 +1,107 @@
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestIdentityProviderReferences(t *testing.T) {
+	ca := configv1.ConfigMapNameReference{Name: "ca"}
+	cert := configv1.SecretNameReference{Name: "cert"}
+	key := configv1.SecretNameReference{Name: "key"}
+	secret := configv1.SecretNameReference{Name: "secret"}
+
+	tests := []struct {
+		providerConfig configv1.IdentityProviderConfig
+		expectedFields []string
+	}{
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:      configv1.IdentityProviderTypeBasicAuth,
+				BasicAuth: &configv1.BasicAuthIdentityProvider{OAuthRemoteConnectionInfo: configv1.OAuthRemoteConnectionInfo{CA: ca, TLSClientCert: cert, TLSClientKey: key}},
+			},
+			expectedFields: []string{"ca", "tls-client-cert", "tls-client-key"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeGitHub,
+				GitHub: &configv1.GitHubIdentityProvider{ClientSecret: secret, CA: ca},
+			},
+			expectedFields: []string{"ca", "client-secret"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeGitLab,
+				GitLab: &configv1.GitLabIdentityProvider{ClientSecret: secret},
+			},
+			expectedFields: []string{"client-secret"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeGoogle,
+				Google: &configv1.GoogleIdentityProvider{ClientSecret: secret},
+			},
+			expectedFields: []string{"client-secret"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:     configv1.IdentityProviderTypeHTPasswd,
+				HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: secret},
+			},
+			expectedFields: []string{"file-data"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:     configv1.IdentityProviderTypeKeystone,
+				Keystone: &configv1.KeystoneIdentityProvider{OAuthRemoteConnectionInfo: configv1.OAuthRemoteConnectionInfo{CA: ca, TLSClientCert: cert, TLSClientKey: key}},
+			},
+			expectedFields: []string{"ca", "tls-client-cert", "tls-client-key"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type: configv1.IdentityProviderTypeLDAP,
+				LDAP: &configv1.LDAPIdentityProvider{BindPassword: secret, CA: ca},
+			},
+			expectedFields: []string{"bind-password", "ca"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeOpenID,
+				OpenID: &configv1.OpenIDIdentityProvider{ClientSecret: secret, CA: ca},
+			},
+			expectedFields: []string{"ca", "client-secret"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{
+				Type:          configv1.IdentityProviderTypeRequestHeader,
+				RequestHeader: &configv1.RequestHeaderIdentityProvider{ClientCA: ca},
+			},
+			expectedFields: []string{"ca"},
+		},
+		{
+			providerConfig: configv1.IdentityProviderConfig{Type: configv1.IdentityProviderTypeGitHub},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(string(tt.providerConfig.Type), func(t *testing.T) {
+			references := IdentityProviderReferences(&tt.providerConfig, i)
+
+			raw, err := references.Bytes()
+			require.NoError(t, err)
+			synced := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(raw, &synced))
+			require.Len(t, synced, len(tt.expectedFields))
+
+			for _, field := range tt.expectedFields {
+				require.Equal(t, fmt.Sprintf("v4-0-config-user-idp-%d-%s", i, field), path.Base(path.Dir(references.IDPPath(i, field))))
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package oauth
+
+import (
+	"fmt"
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// validateIdentityProviders checks the structure of the identity providers before they are
+// converted, the conversion reaches out to the identity providers and its errors would not
+// point at the misconfigured field. Only what the oauth-server would refuse is rejected.
+func validateIdentityProviders(identityProviders []configv1.IdentityProvider) []error {
+	allErrs := field.ErrorList{}
+	for i, idp := range identityProviders {
+		allErrs = append(allErrs, validateIdentityProvider(field.NewPath("spec", "identityProviders").Index(i), &idp)...)
+	}
+
+	errs := []error{}
+	for _, err := range allErrs {
+		errs = append(errs, fmt.Errorf("oauth.config.openshift.io/cluster: %v", err))
+	}
+	return errs
+}
+
+func validateIdentityProvider(path *field.Path, idp *configv1.IdentityProvider) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(idp.Name) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+	}
+
+	// the missing provider configs are reported by the conversion
+	switch config := idp.IdentityProviderConfig; config.Type {
+	case configv1.IdentityProviderTypeBasicAuth:
+		if c := config.BasicAuth; c != nil {
+			allErrs = append(allErrs, validateRemoteConnectionInfo(path.Child("basicAuth"), c.OAuthRemoteConnectionInfo)...)
+		}
+	case configv1.IdentityProviderTypeGitHub:
+		if c := config.GitHub; c != nil {
+			allErrs = append(allErrs, validateOAuthClient(path.Child("github"), c.ClientID, c.ClientSecret)...)
+			if len(c.Organizations) > 0 && len(c.Teams) > 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child("github", "teams"), c.Teams, "specify either organizations or teams, not both"))
+			}
+		}
+	case configv1.IdentityProviderTypeGitLab:
+		if c := config.GitLab; c != nil {
+			allErrs = append(allErrs, validateURL(path.Child("gitlab", "url"), c.URL)...)
+			allErrs = append(allErrs, validateOAuthClient(path.Child("gitlab"), c.ClientID, c.ClientSecret)...)
+		}
+	case configv1.IdentityProviderTypeGoogle:
+		if c := config.Google; c != nil {
+			allErrs = append(allErrs, validateOAuthClient(path.Child("google"), c.ClientID, c.ClientSecret)...)
+		}
+	case configv1.IdentityProviderTypeHTPasswd:
+		if c := config.HTPasswd; c != nil && len(c.FileData.Name) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("htpasswd", "fileData", "name"), ""))
+		}
+	case configv1.IdentityProviderTypeKeystone:
+		if c := config.Keystone; c != nil {
+			allErrs = append(allErrs, validateRemoteConnectionInfo(path.Child("keystone"), c.OAuthRemoteConnectionInfo)...)
+			if len(c.DomainName) == 0 {
+				allErrs = append(allErrs, field.Required(path.Child("keystone", "domainName"), ""))
+			}
+		}
+	case configv1.IdentityProviderTypeLDAP:
+		if c := config.LDAP; c != nil {
+			allErrs = append(allErrs, validateLDAPURL(path.Child("ldap", "url"), c.URL)...)
+			if len(c.BindPassword.Name) > 0 && len(c.BindDN) == 0 {
+				allErrs = append(allErrs, field.Required(path.Child("ldap", "bindDN"), "the bind password is set"))
+			}
+		}
+	case configv1.IdentityProviderTypeOpenID:
+		if c := config.OpenID; c != nil {
+			allErrs = append(allErrs, validateURL(path.Child("openID", "issuer"), c.Issuer)...)
+			allErrs = append(allErrs, validateOAuthClient(path.Child("openID"), c.ClientID, c.ClientSecret)...)
+		}
+	case configv1.IdentityProviderTypeRequestHeader:
+		if c := config.RequestHeader; c != nil {
+			if len(c.Headers) == 0 {
+				allErrs = append(allErrs, field.Required(path.Child("requestHeader", "headers"), ""))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateOAuthClient(path *field.Path, clientID string, clientSecret configv1.SecretNameReference) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(clientID) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("clientID"), ""))
+	}
+	if len(clientSecret.Name) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("clientSecret", "name"), ""))
+	}
+	return allErrs
+}
+
+func validateRemoteConnectionInfo(path *field.Path, info configv1.OAuthRemoteConnectionInfo) field.ErrorList {
+	allErrs := validateURL(path.Child("url"), info.URL)
+	if hasCert, hasKey := len(info.TLSClientCert.Name) > 0, len(info.TLSClientKey.Name) > 0; hasCert != hasKey {
+		allErrs = append(allErrs, field.Invalid(path.Child("tlsClientCert"), info.TLSClientCert.Name, "tlsClientCert and tlsClientKey must be set together"))
+	}
+	return allErrs
+}
+
+func validateURL(path *field.Path, rawURL string) field.ErrorList {
+	if len(rawURL) == 0 {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, rawURL, err.Error())}
+	}
+	if u.Scheme != "https" || len(u.Host) == 0 {
+		return field.ErrorList{field.Invalid(path, rawURL, "must be an https URL")}
+	}
+	return nil
+}
+
+func validateLDAPURL(path *field.Path, rawURL string) field.ErrorList {
+	if len(rawURL) == 0 {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, rawURL, err.Error())}
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return field.ErrorList{field.Invalid(path, rawURL, "must be an ldap:// or ldaps:// URL")}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestValidateIdentityProviders(t *testing.T) {
+	secret := configv1.SecretNameReference{Name: "secret"}
+
+	tests := []struct {
+		name           string
+		idp            configv1.IdentityProviderConfig
+		expectedErrors []string
+	}{
+		{
+			name: "valid github",
+			idp: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeGitHub,
+				GitHub: &configv1.GitHubIdentityProvider{ClientID: "client", ClientSecret: secret, Organizations: []string{"org"}},
+			},
+		},
+		{
+			name: "github without a client and with both organizations and teams",
+			idp: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeGitHub,
+				GitHub: &configv1.GitHubIdentityProvider{Organizations: []string{"org"}, Teams: []string{"org/team"}},
+			},
+			expectedErrors: []string{
+				"oauth.config.openshift.io/cluster: spec.identityProviders[0].github.clientID: Required value",
+				"oauth.config.openshift.io/cluster: spec.identityProviders[0].github.clientSecret.name: Required value",
+				`oauth.config.openshift.io/cluster: spec.identityProviders[0].github.teams: Invalid value: []string{"org/team"}: specify either organizations or teams, not both`,
+			},
+		},
+		{
+			name: "htpasswd without a file",
+			idp: configv1.IdentityProviderConfig{
+				Type:     configv1.IdentityProviderTypeHTPasswd,
+				HTPasswd: &configv1.HTPasswdIdentityProvider{},
+			},
+			expectedErrors: []string{"oauth.config.openshift.io/cluster: spec.identityProviders[0].htpasswd.fileData.name: Required value"},
+		},
+		{
+			name: "basic auth over http with a cert but no key",
+			idp: configv1.IdentityProviderConfig{
+				Type: configv1.IdentityProviderTypeBasicAuth,
+				BasicAuth: &configv1.BasicAuthIdentityProvider{OAuthRemoteConnectionInfo: configv1.OAuthRemoteConnectionInfo{
+					URL:           "http://auth.example.com",
+					TLSClientCert: secret,
+				}},
+			},
+			expectedErrors: []string{
+				`oauth.config.openshift.io/cluster: spec.identityProviders[0].basicAuth.url: Invalid value: "http://auth.example.com": must be an https URL`,
+				`oauth.config.openshift.io/cluster: spec.identityProviders[0].basicAuth.tlsClientCert: Invalid value: "secret": tlsClientCert and tlsClientKey must be set together`,
+			},
+		},
+		{
+			name: "keystone without a domain",
+			idp: configv1.IdentityProviderConfig{
+				Type:     configv1.IdentityProviderTypeKeystone,
+				Keystone: &configv1.KeystoneIdentityProvider{OAuthRemoteConnectionInfo: configv1.OAuthRemoteConnectionInfo{URL: "https://keystone.example.com"}},
+			},
+			expectedErrors: []string{"oauth.config.openshift.io/cluster: spec.identityProviders[0].keystone.domainName: Required value"},
+		},
+		{
+			name: "ldap with a bind password but no bind DN",
+			idp: configv1.IdentityProviderConfig{
+				Type: configv1.IdentityProviderTypeLDAP,
+				LDAP: &configv1.LDAPIdentityProvider{URL: "https://ldap.example.com", BindPassword: secret},
+			},
+			expectedErrors: []string{
+				`oauth.config.openshift.io/cluster: spec.identityProviders[0].ldap.url: Invalid value: "https://ldap.example.com": must be an ldap:// or ldaps:// URL`,
+				"oauth.config.openshift.io/cluster: spec.identityProviders[0].ldap.bindDN: Required value: the bind password is set",
+			},
+		},
+		{
+			name: "valid ldap",
+			idp: configv1.IdentityProviderConfig{
+				Type: configv1.IdentityProviderTypeLDAP,
+				LDAP: &configv1.LDAPIdentityProvider{URL: "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid", BindDN: "cn=admin", BindPassword: secret},
+			},
+		},
+		{
+			name: "openid without an issuer",
+			idp: configv1.IdentityProviderConfig{
+				Type:   configv1.IdentityProviderTypeOpenID,
+				OpenID: &configv1.OpenIDIdentityProvider{ClientID: "client", ClientSecret: secret},
+			},
+			expectedErrors: []string{"oauth.config.openshift.io/cluster: spec.identityProviders[0].openID.issuer: Required value"},
+		},
+		{
+			name: "request header without headers",
+			idp: configv1.IdentityProviderConfig{
+				Type:          configv1.IdentityProviderTypeRequestHeader,
+				RequestHeader: &configv1.RequestHeaderIdentityProvider{},
+			},
+			expectedErrors: []string{"oauth.config.openshift.io/cluster: spec.identityProviders[0].requestHeader.headers: Required value"},
+		},
+		{
+			name: "missing provider config is left to the conversion",
+			idp:  configv1.IdentityProviderConfig{Type: configv1.IdentityProviderTypeGoogle},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateIdentityProviders([]configv1.IdentityProvider{{Name: "idp", IdentityProviderConfig: tt.idp}})
+			gotErrors := []string{}
+			for _, err := range errs {
+				gotErrors = append(gotErrors, err.Error())
+			}
+			if len(tt.expectedErrors) == 0 {
+				require.Empty(t, gotErrors)
+				return
+			}
+			require.Equal(t, tt.expectedErrors, gotErrors)
+		})
+	}
+}
+
+func TestValidateIdentityProvidersName(t *testing.T) {
+	errs := validateIdentityProviders([]configv1.IdentityProvider{{
+		IdentityProviderConfig: configv1.IdentityProviderConfig{
+			Type:     configv1.IdentityProviderTypeHTPasswd,
+			HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: configv1.SecretNameReference{Name: "htpasswd"}},
+		},
+	}})
+	require.Len(t, errs, 1)
+	require.Equal(t, "oauth.config.openshift.io/cluster: spec.identityProviders[0].name: Required value", errs[0].Error())
+}
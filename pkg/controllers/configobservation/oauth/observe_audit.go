@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -29,6 +30,11 @@ var (
 	}
 )
 
+// AuditArguments returns a copy of the oauth-server arguments that turn the audit log on
+func AuditArguments() map[string]interface{} {
+	return runtime.DeepCopyJSON(auditOptionsArgs)
+}
+
 func ObserveAudit(
 	genericListers configobserver.Listers,
 	recorder events.Recorder,
@@ -56,6 +62,18 @@ func ObserveAudit(
 		observedAuditProfile = apiServer.Spec.Audit.Profile
 	}
 
+	// the oauth-server only ever logs request metadata as the request bodies would
+	// contain user credentials, the profile can therefore only turn the audit off
+	switch observedAuditProfile {
+	case "",
+		configv1.NoneAuditProfileType,
+		configv1.DefaultAuditProfileType,
+		configv1.WriteRequestBodiesAuditProfileType,
+		configv1.AllRequestBodiesAuditProfileType:
+	default:
+		return existingConfig, append(errs, fmt.Errorf("unsupported audit profile %q", observedAuditProfile))
+	}
+
 	observedConfig := map[string]interface{}{}
 	if observedAuditProfile != configv1.NoneAuditProfileType {
 		if err := unstructured.SetNestedField(
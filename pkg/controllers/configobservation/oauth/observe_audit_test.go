@@ -1,6 +1,7 @@
 package oauth_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -107,6 +108,20 @@ func TestAuditProfile(t *testing.T) {
 			previouslyObservedConfig: map[string]interface{}{},
 			expected:                 auditOpts,
 		},
+		{
+			name: "unknown profile keeps the previous config",
+			config: &configv1.APIServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.APIServerSpec{
+					Audit: configv1.Audit{
+						Profile: "Everything",
+					},
+				},
+			},
+			previouslyObservedConfig: auditOpts,
+			expected:                 auditOpts,
+			errors:                   []error{fmt.Errorf(`unsupported audit profile "Everything"`)},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
@@ -121,8 +136,8 @@ func TestAuditProfile(t *testing.T) {
 			}
 
 			have, errs := oauth.ObserveAudit(listers, events.NewInMemoryRecorder(t.Name()), tt.previouslyObservedConfig)
-			if len(errs) > 0 {
-				t.Errorf("Expected 0 errors, have %v.", len(errs))
+			if len(errs) != len(tt.errors) {
+				t.Errorf("Expected %d errors, have %v: %v", len(tt.errors), len(errs), errs)
 			}
 
 			if !equality.Semantic.DeepEqual(tt.expected, have) {
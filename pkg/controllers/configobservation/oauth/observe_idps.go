@@ -1,12 +1,15 @@
 package oauth
 
 import (
+	"fmt"
+
 	"k8s.io/klog/v2"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver"
 	"github.com/openshift/library-go/pkg/operator/events"
 
@@ -16,6 +19,9 @@ import (
 
 var identityProvidersMounts = []string{"volumesToMount", "identityProviders"}
 
+// maxReportedIdentityProviderErrors limits the identity provider errors reported at once
+const maxReportedIdentityProviderErrors = 10
+
 func ObserveIdentityProviders(genericlisters configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (ret map[string]interface{}, errs []error) {
 	identityProvidersPath := []string{"oauthConfig", "identityProviders"}
 	defer func() {
@@ -50,11 +56,20 @@ func ObserveIdentityProviders(genericlisters configobserver.Listers, recorder ev
 		return existingConfig, append(errs, err)
 	}
 
+	// collect all the problems of the identity providers so that they can be fixed at once
+	idpErrs := validateIdentityProviderNames(oauthConfig.Spec.IdentityProviders)
+	if structuralErrs := validateIdentityProviders(oauthConfig.Spec.IdentityProviders); len(structuralErrs) > 0 {
+		// don't convert a malformed config, the conversion errors would only repeat these
+		return existingConfig, append(errs, limitErrors(append(idpErrs, structuralErrs...), maxReportedIdentityProviderErrors)...)
+	}
+
 	// convert identity providers from config to oauth-configuration API and
 	// extract the CMs and Secrets that need to be synchronized to the target NS
-	convertedObservedIdentityProviders, observedSyncData, idpErrs := convertIdentityProviders(listers.ConfigMapLister, listers.SecretsLister, oauthConfig.Spec.IdentityProviders)
+	convertedObservedIdentityProviders, observedSyncData, conversionErrs := convertIdentityProviders(listers.ConfigMapLister, listers.SecretsLister, oauthConfig.Spec.IdentityProviders)
+	idpErrs = append(idpErrs, conversionErrs...)
+	idpErrs = append(idpErrs, observedSyncData.Validate(listers.ConfigMapLister, listers.SecretsLister)...)
 	if len(idpErrs) > 0 {
-		return existingConfig, append(errs, idpErrs...)
+		return existingConfig, append(errs, limitErrors(idpErrs, maxReportedIdentityProviderErrors)...)
 	}
 
 	observedConfig := map[string]interface{}{}
@@ -73,10 +88,6 @@ func ObserveIdentityProviders(genericlisters configobserver.Listers, recorder ev
 		recorder.Eventf("ObserveIdentityProviders", "identity providers changed to %q", convertedObservedIdentityProviders)
 	}
 
-	if syncDataErrs := observedSyncData.Validate(listers.ConfigMapLister, listers.SecretsLister); len(syncDataErrs) > 0 {
-		return existingConfig, append(errs, syncDataErrs...)
-	}
-
 	datasync.HandleIdPConfigSync(resourceSyncer, existingSyncData, observedSyncData)
 
 	if err := unstructured.SetNestedField(observedConfig, string(observedSyncDataBytes), identityProvidersMounts...); err != nil {
@@ -86,6 +97,29 @@ func ObserveIdentityProviders(genericlisters configobserver.Listers, recorder ev
 	return observedConfig, errs
 }
 
+// validateIdentityProviderNames rejects identity providers that share a name, the identities
+// are keyed by the provider name and the oauth-server would refuse such a config
+func validateIdentityProviderNames(identityProviders []configv1.IdentityProvider) []error {
+	errs := []error{}
+	seen := map[string]int{}
+	for i, idp := range identityProviders {
+		if first, ok := seen[idp.Name]; ok {
+			errs = append(errs, fmt.Errorf("identity providers %d and %d of oauth.config.openshift.io/cluster are both named %q, the names must be unique", first, i, idp.Name))
+			continue
+		}
+		seen[idp.Name] = i
+	}
+	return errs
+}
+
+// limitErrors keeps the first max errors so that the degraded condition stays readable
+func limitErrors(errs []error, max int) []error {
+	if len(errs) <= max {
+		return errs
+	}
+	return append(errs[:max:max], fmt.Errorf("%d more identity provider errors are not shown", len(errs)-max))
+}
+
 // GetIDPConfigSyncData returns the data that should be synchronized and mounted
 // to the oauth-server container from the observed configuration
 func GetIDPConfigSyncData(observedConfig map[string]interface{}) (*datasync.ConfigSyncData, error) {
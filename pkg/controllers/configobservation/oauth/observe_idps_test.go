@@ -172,6 +172,78 @@ func TestObserveIdentityProviders(t *testing.T) {
 			expectedEvents: 1,
 			errors:         []error{},
 		},
+		{
+			name: "duplicate IdP names",
+			config: &configv1.OAuth{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.OAuthSpec{
+					IdentityProviders: []configv1.IdentityProvider{
+						{
+							Name: "corp",
+							IdentityProviderConfig: configv1.IdentityProviderConfig{
+								Type:     configv1.IdentityProviderTypeHTPasswd,
+								HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: configv1.SecretNameReference{Name: "somesecret"}},
+							},
+						},
+						{
+							Name: "corp",
+							IdentityProviderConfig: configv1.IdentityProviderConfig{
+								Type:     configv1.IdentityProviderTypeHTPasswd,
+								HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: configv1.SecretNameReference{Name: "othersecret"}},
+							},
+						},
+					},
+				},
+			},
+			configSecrets: []*corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "somesecret", Namespace: "openshift-config"},
+					Data:       map[string][]byte{"htpasswd": []byte("something")},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "othersecret", Namespace: "openshift-config"},
+					Data:       map[string][]byte{"htpasswd": []byte("something")},
+				},
+			},
+			previouslyObservedConfig: map[string]interface{}{},
+			previousSyncerData:       map[string]string{},
+			expected:                 map[string]interface{}{},
+			expectedSyncerData:       map[string]string{},
+			errors:                   []error{fmt.Errorf(`identity providers 0 and 1 of oauth.config.openshift.io/cluster are both named "corp", the names must be unique`)},
+		},
+		{
+			name: "multiple configuration errors",
+			config: &configv1.OAuth{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.OAuthSpec{
+					IdentityProviders: []configv1.IdentityProvider{
+						{
+							Name: "corp",
+							IdentityProviderConfig: configv1.IdentityProviderConfig{
+								Type:     configv1.IdentityProviderTypeHTPasswd,
+								HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: configv1.SecretNameReference{Name: "somesecret"}},
+							},
+						},
+						{
+							Name: "corp",
+							IdentityProviderConfig: configv1.IdentityProviderConfig{
+								Type:     configv1.IdentityProviderTypeHTPasswd,
+								HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: configv1.SecretNameReference{Name: "othersecret"}},
+							},
+						},
+					},
+				},
+			},
+			previouslyObservedConfig: map[string]interface{}{},
+			previousSyncerData:       map[string]string{},
+			expected:                 map[string]interface{}{},
+			expectedSyncerData:       map[string]string{},
+			errors: []error{
+				fmt.Errorf(`identity providers 0 and 1 of oauth.config.openshift.io/cluster are both named "corp", the names must be unique`),
+				fmt.Errorf(`error validating secret openshift-config/somesecret: secret "somesecret" not found`),
+				fmt.Errorf(`error validating secret openshift-config/othersecret: secret "othersecret" not found`),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -203,8 +275,14 @@ func TestObserveIdentityProviders(t *testing.T) {
 
 			got, errs := ObserveIdentityProviders(listers, eventsRecorder, tt.previouslyObservedConfig)
 
-			if len(errs) > 0 {
-				t.Errorf("Expected 0 errors, got %v.", errs)
+			if len(errs) != len(tt.errors) {
+				t.Errorf("Expected %d errors, got %v.", len(tt.errors), errs)
+			} else {
+				for i := range errs {
+					if errs[i].Error() != tt.errors[i].Error() {
+						t.Errorf("Expected error %q, got %q.", tt.errors[i], errs[i])
+					}
+				}
 			}
 
 			if gotEvents := eventsRecorder.Events(); tt.expectedEvents != len(gotEvents) {
@@ -221,6 +299,24 @@ func TestObserveIdentityProviders(t *testing.T) {
 	}
 }
 
+func TestLimitErrors(t *testing.T) {
+	errs := []error{}
+	for i := 0; i < 12; i++ {
+		errs = append(errs, fmt.Errorf("error %d", i))
+	}
+
+	limited := limitErrors(errs, 10)
+	if len(limited) != 11 {
+		t.Fatalf("expected 10 errors and a note, got %v", limited)
+	}
+	if got := limited[10].Error(); got != "2 more identity provider errors are not shown" {
+		t.Errorf("unexpected note %q", got)
+	}
+	if len(limitErrors(errs[:3], 10)) != 3 {
+		t.Errorf("expected the errors below the limit to be kept as they are")
+	}
+}
+
 func eventsReasonMessage(e []*corev1.Event) []string {
 	reasonMessages := make([]string, 0, len(e))
 	for _, ev := range e {
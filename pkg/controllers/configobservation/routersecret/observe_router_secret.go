@@ -53,7 +53,7 @@ func ObserveRouterSecret(genericlisters configobserver.Listers, recorder events.
 func getObservedNamedCertificates(listers configobservation.Listers) ([]interface{}, error) {
 	// Check for custom serving certificate secret
 	defaultSecretName := "v4-0-config-system-router-certs"
-	secret, err := common.GetActiveRouterSecret(listers.SecretsLister, "openshift-authentication", defaultSecretName, "v4-0-config-system-custom-router-certs")
+	secret, err := common.GetActiveRouterSecret(listers.SecretsLister, common.TargetNamespace, defaultSecretName, "v4-0-config-system-custom-router-certs")
 	if err != nil {
 		return nil, err
 	}
@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 	"github.com/openshift/library-go/pkg/route/routeapihelpers"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 )
@@ -75,7 +77,11 @@ func checkIngressURI(ingressConfig *configv1.Ingress, route *routev1.Route) []me
 			Reason:             "RouteNotAdmitted",
 			Message:            fmt.Sprintf("Route not admitted: %v", err),
 		}
-		componentRoute := common.GetComponentRouteStatus(ingressConfig, "openshift-authentication", "oauth-openshift")
+		if rejection := routeRejection(route); len(rejection) > 0 {
+			condition.Reason = "RouteRejected"
+			condition.Message = fmt.Sprintf("Route not admitted: %s", rejection)
+		}
+		componentRoute := common.GetComponentRouteStatus(ingressConfig, common.TargetNamespace, "oauth-openshift")
 		if componentRoute != nil {
 			degradeIfTimeElapsed(componentRoute.Conditions, condition, time.Minute*5)
 		}
@@ -84,6 +90,26 @@ func checkIngressURI(ingressConfig *configv1.Ingress, route *routev1.Route) []me
 	return nil
 }
 
+// routeRejection describes why the routers that explicitly refused the route did so,
+// e.g. because of their wildcard or namespace ownership policies
+func routeRejection(route *routev1.Route) string {
+	rejections := []string{}
+	for _, ingress := range route.Status.Ingress {
+		if ingress.Host != route.Spec.Host {
+			continue
+		}
+		if ingress.WildcardPolicy != "" && ingress.WildcardPolicy != route.Spec.WildcardPolicy {
+			rejections = append(rejections, fmt.Sprintf("router %q admits the route with the wildcard policy %q instead of %q", ingress.RouterName, ingress.WildcardPolicy, route.Spec.WildcardPolicy))
+		}
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionFalse {
+				rejections = append(rejections, fmt.Sprintf("router %q rejected the route: %s: %s", ingress.RouterName, condition.Reason, condition.Message))
+			}
+		}
+	}
+	return strings.Join(rejections, "; ")
+}
+
 // degradeIfTimeElapsed checks if the condition matching this error (same type, reason and message)
 // was found in the set of conditions and its `lastTransitionTime` appeared longer than
 // `maxAge` ago, if so the condition's type is set to "Degraded"
@@ -99,9 +125,31 @@ func degradeIfTimeElapsed(conditions []metav1.Condition, condition *metav1.Condi
 	}
 }
 
-func checkRouteAvailablity(secretLister corev1listers.SecretLister, ingressConfig *configv1.Ingress, route *routev1.Route) []metav1.Condition {
+// checkRouteServingCert reports a route host the serving certificate in use does not cover,
+// e.g. a host template outside of the wildcard of the default ingress controller. The route
+// is applied regardless, the router serves it once a matching certificate is configured.
+func checkRouteServingCert(secretLister corev1listers.SecretLister, ingressConfig *configv1.Ingress, route *routev1.Route) []metav1.Condition {
+	certBytes, _, _, err := common.GetActiveRouterCertKeyBytes(secretLister, ingressConfig, common.TargetNamespace, "v4-0-config-system-router-certs", "v4-0-config-system-custom-router-certs")
+	if err != nil || len(certBytes) == 0 {
+		// a missing certificate is reported by the availability check
+		return nil
+	}
+
+	if err := validateCertificateHostname(certBytes, route.Spec.Host); err != nil {
+		return []metav1.Condition{{
+			LastTransitionTime: metav1.Now(),
+			Type:               "Degraded",
+			Status:             metav1.ConditionTrue,
+			Reason:             "ServingCertHostMismatch",
+			Message:            fmt.Sprintf("The serving certificate of the route does not cover its host: %v", err),
+		}}
+	}
+	return nil
+}
+
+func checkRouteAvailablity(secretLister corev1listers.SecretLister, ingressConfig *configv1.Ingress, route *routev1.Route, healthPath string) []metav1.Condition {
 	now := metav1.Now()
-	if err := routeAvailablity(secretLister, route.Spec.Host, ingressConfig); err != nil {
+	if err := routeAvailablity(secretLister, route.Spec.Host, healthPath, ingressConfig); err != nil {
 		condition := &metav1.Condition{
 			LastTransitionTime: now,
 			Type:               "Progressing",
@@ -109,7 +157,7 @@ func checkRouteAvailablity(secretLister corev1listers.SecretLister, ingressConfi
 			Reason:             "ErrorReachingOutToService",
 			Message:            fmt.Sprintf("unexpected error at %s: %v", route.Spec.Host, err),
 		}
-		componentRoute := common.GetComponentRouteStatus(ingressConfig, "openshift-authentication", "oauth-openshift")
+		componentRoute := common.GetComponentRouteStatus(ingressConfig, common.TargetNamespace, "oauth-openshift")
 		if componentRoute != nil {
 			degradeIfTimeElapsed(componentRoute.Conditions, condition, time.Minute*5)
 		}
@@ -119,8 +167,8 @@ func checkRouteAvailablity(secretLister corev1listers.SecretLister, ingressConfi
 	return nil
 }
 
-func routeAvailablity(secretLister corev1listers.SecretLister, host string, ingress *configv1.Ingress) error {
-	url := "https://" + host + "/healthz"
+func routeAvailablity(secretLister corev1listers.SecretLister, host, healthPath string, ingress *configv1.Ingress) error {
+	url := "https://" + host + healthPath
 
 	reqCtx, cancel := context.WithTimeout(context.TODO(), 10*time.Second) // avoid waiting forever
 	defer cancel()
@@ -130,7 +178,7 @@ func routeAvailablity(secretLister corev1listers.SecretLister, host string, ingr
 		return err
 	}
 
-	certBytes, _, _, err := common.GetActiveRouterCertKeyBytes(secretLister, ingress, "openshift-authentication", "v4-0-config-system-router-certs", "v4-0-config-system-custom-router-certs")
+	certBytes, _, _, err := common.GetActiveRouterCertKeyBytes(secretLister, ingress, common.TargetNamespace, "v4-0-config-system-router-certs", "v4-0-config-system-custom-router-certs")
 	if err != nil {
 		return err
 	}
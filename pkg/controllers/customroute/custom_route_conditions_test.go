@@ -0,0 +1,137 @@
+package customroute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+func TestCheckIngressURI(t *testing.T) {
+	tests := []struct {
+		name            string
+		ingress         []routev1.RouteIngress
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "admitted",
+			ingress: []routev1.RouteIngress{{
+				Host:           "oauth-openshift.apps.example.com",
+				RouterName:     "default",
+				WildcardPolicy: routev1.WildcardPolicyNone,
+				Conditions:     []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+			}},
+		},
+		{
+			name:            "not yet seen by any router",
+			expectedReason:  "RouteNotAdmitted",
+			expectedMessage: "Route not admitted: no ingress for host oauth-openshift.apps.example.com in route oauth-openshift in namespace openshift-authentication",
+		},
+		{
+			name: "rejected by the router",
+			ingress: []routev1.RouteIngress{{
+				Host:       "oauth-openshift.apps.example.com",
+				RouterName: "default",
+				Conditions: []routev1.RouteIngressCondition{{
+					Type:    routev1.RouteAdmitted,
+					Status:  corev1.ConditionFalse,
+					Reason:  "RouteNotAdmitted",
+					Message: "wildcard routes are not allowed",
+				}},
+			}},
+			expectedReason:  "RouteRejected",
+			expectedMessage: `Route not admitted: router "default" rejected the route: RouteNotAdmitted: wildcard routes are not allowed`,
+		},
+		{
+			name: "unexpected wildcard policy",
+			ingress: []routev1.RouteIngress{{
+				Host:           "oauth-openshift.apps.example.com",
+				RouterName:     "sharded",
+				WildcardPolicy: routev1.WildcardPolicySubdomain,
+			}},
+			expectedReason:  "RouteRejected",
+			expectedMessage: `Route not admitted: router "sharded" admits the route with the wildcard policy "Subdomain" instead of "None"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &routev1.Route{}
+			route.Namespace, route.Name = "openshift-authentication", "oauth-openshift"
+			route.Spec.Host = "oauth-openshift.apps.example.com"
+			route.Spec.WildcardPolicy = routev1.WildcardPolicyNone
+			route.Status.Ingress = tt.ingress
+
+			conditions := checkIngressURI(&configv1.Ingress{}, route)
+			if len(tt.expectedReason) == 0 {
+				require.Empty(t, conditions)
+				return
+			}
+			require.Len(t, conditions, 1)
+			require.Equal(t, tt.expectedReason, conditions[0].Reason)
+			require.Equal(t, tt.expectedMessage, conditions[0].Message)
+		})
+	}
+}
+
+func TestCheckRouteServingCert(t *testing.T) {
+	certData, _, err := certutil.GenerateSelfSignedCertKey("router", nil, []string{"*.apps.example.com"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		host           string
+		withoutSecret  bool
+		expectedReason string
+	}{
+		{
+			name: "host within the wildcard",
+			host: "oauth-openshift.apps.example.com",
+		},
+		{
+			name:           "host two labels below the domain",
+			host:           "oauth.login.apps.example.com",
+			expectedReason: "ServingCertHostMismatch",
+		},
+		{
+			name:           "host outside the domain",
+			host:           "login.example.com",
+			expectedReason: "ServingCertHostMismatch",
+		},
+		{
+			name:          "no serving certificate yet",
+			host:          "login.example.com",
+			withoutSecret: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if !tt.withoutSecret {
+				require.NoError(t, indexer.Add(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "v4-0-config-system-router-certs"},
+					Data:       map[string][]byte{"apps.example.com": certData},
+				}))
+			}
+			ingressConfig := &configv1.Ingress{Spec: configv1.IngressSpec{Domain: "apps.example.com"}}
+			route := &routev1.Route{Spec: routev1.RouteSpec{Host: tt.host}}
+
+			conditions := checkRouteServingCert(corev1listers.NewSecretLister(indexer), ingressConfig, route)
+			if len(tt.expectedReason) == 0 {
+				require.Empty(t, conditions)
+				return
+			}
+			require.Len(t, conditions, 1)
+			require.Equal(t, "Degraded", conditions[0].Type)
+			require.Equal(t, tt.expectedReason, conditions[0].Reason)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -13,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -38,7 +40,9 @@ import (
 
 const (
 	OAuthComponentRouteName      = "oauth-openshift"
-	OAuthComponentRouteNamespace = "openshift-authentication"
+	OAuthComponentRouteNamespace = common.TargetNamespace
+
+	routeTimeoutAnnotation = "haproxy.router.openshift.io/timeout"
 )
 
 type customRouteController struct {
@@ -46,6 +50,7 @@ type customRouteController struct {
 	componentRoute types.NamespacedName
 	ingressLister  configlistersv1.IngressLister
 	ingressClient  configsetterv1.IngressInterface
+	infraLister    configlistersv1.InfrastructureLister
 	routeLister    routev1lister.RouteLister
 	routeClient    routeclient.RouteInterface
 	secretLister   corev1listers.SecretLister
@@ -60,6 +65,7 @@ func NewCustomRouteController(
 	destSecretName string,
 	ingressInformer configinformers.IngressInformer,
 	ingressClient configsetterv1.IngressInterface,
+	infraInformer configinformers.InfrastructureInformer,
 	routeInformer routeinformer.RouteInformer,
 	routeClient routeclient.RouteInterface,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
@@ -78,6 +84,7 @@ func NewCustomRouteController(
 		},
 		ingressLister:  ingressInformer.Lister(),
 		ingressClient:  ingressClient,
+		infraLister:    infraInformer.Lister(),
 		routeLister:    routeInformer.Lister(),
 		routeClient:    routeClient,
 		secretLister:   kubeInformersForNamespaces.SecretLister(),
@@ -88,10 +95,13 @@ func NewCustomRouteController(
 	return factory.New().
 		WithInformers(
 			ingressInformer.Informer(),
+			infraInformer.Informer(),
 			routeInformer.Informer(),
 			kubeInformersForNamespaces.InformersFor("openshift-config").Core().V1().Secrets().Informer(),
-			kubeInformersForNamespaces.InformersFor("openshift-authentication").Core().V1().Secrets().Informer(),
+			kubeInformersForNamespaces.InformersFor(common.TargetNamespace).Core().V1().Secrets().Informer(),
 		).
+		// the controller writes the operator status, only the spec changes need a sync
+		WithFilteredEventsInformers(common.SpecChangedFilter(), operatorClient.Informer()).
 		WithSyncDegradedOnError(operatorClient).
 		WithSync(controller.sync).
 		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
@@ -99,6 +109,11 @@ func NewCustomRouteController(
 }
 
 func (c *customRouteController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	paused, err := common.IsOperatorReconciliationPaused(c.operatorClient)
+	if err != nil {
+		return err
+	}
+
 	ingressConfig, err := c.ingressLister.Get("cluster")
 	if err != nil {
 		return err
@@ -106,51 +121,115 @@ func (c *customRouteController) sync(ctx context.Context, syncCtx factory.SyncCo
 
 	ingressConfigCopy := ingressConfig.DeepCopy()
 
+	ingressDomain, err := common.NormalizeIngressDomain(ingressConfig.Spec.Domain)
+	if err != nil {
+		return err
+	}
+
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	routeOverrides, err := common.GetRouteOverrides(operatorSpec)
+	if err != nil {
+		return err
+	}
+
 	// configure the expected route
-	expectedRoute, secretName, errors := c.getOAuthRouteAndSecretName(ingressConfigCopy)
+	var expectedRoute *routev1.Route
+	var secretName string
+	var errors []error
+	defaultHost, err := c.getDefaultRouteHost(routeOverrides.HostTemplate, ingressDomain)
+	if err != nil {
+		defaultHost = defaultRouteHost(ingressDomain)
+		errors = []error{err}
+	} else {
+		expectedRoute, secretName, errors = c.getOAuthRouteAndSecretName(ingressConfigCopy, defaultHost)
+	}
 	if errors != nil {
 		// log if there is an issue updating the ingressConfig resource
-		if updateIngressConfigErr := c.updateIngressConfigStatus(ctx, ingressConfigCopy, errors); updateIngressConfigErr != nil {
+		route, err := c.routeLister.Routes(common.TargetNamespace).Get("oauth-openshift")
+		if err == nil {
+			err = c.updateIngressConfigStatus(ctx, ingressConfigCopy, defaultHost, route, routeOverrides.HealthPath, errors)
+		}
+		if err != nil {
 			klog.Infof("Error updating ingress with custom route status: %v", err)
 		}
 		return fmt.Errorf("custom route configuration failed verification: %v", errors)
 	}
 
-	// create or modify the existing route
-	if err = c.applyRoute(ctx, expectedRoute); err != nil {
+	if expectedRoute.Annotations == nil {
+		expectedRoute.Annotations = map[string]string{}
+	}
+	expectedRoute.Annotations[routeTimeoutAnnotation] = routeOverrides.Timeout
+
+	// create or modify the existing route, the route that was just recreated after
+	// a deletion might not have reached the lister yet so use the applied one
+	var route *routev1.Route
+	if paused {
+		// the route is left as it is while paused, its status is still reported
+		klog.V(4).Infof("reconciliation is paused, skipping the route and secret updates")
+		route, err = c.routeLister.Routes(common.TargetNamespace).Get("oauth-openshift")
+	} else {
+		route, err = c.applyRoute(ctx, expectedRoute)
+	}
+	if err != nil {
 		return err
 	}
 
 	// update ingressConfig status
-	if err = c.updateIngressConfigStatus(ctx, ingressConfigCopy, nil); err != nil {
+	if err = c.updateIngressConfigStatus(ctx, ingressConfigCopy, defaultHost, route, routeOverrides.HealthPath, nil); err != nil {
 		return err
 	}
 
+	if paused {
+		return nil
+	}
+
 	// sync the secret
 	return c.syncSecret(secretName)
 }
 
-func (c *customRouteController) getOAuthRouteAndSecretName(ingressConfig *configv1.Ingress) (*routev1.Route, string, []error) {
+// getDefaultRouteHost returns the host of the oauth route when the ingress config does not
+// override it, rendered from the host template of the unsupportedConfigOverrides if set
+func (c *customRouteController) getDefaultRouteHost(hostTemplate, ingressDomain string) (string, error) {
+	if len(hostTemplate) == 0 {
+		return defaultRouteHost(ingressDomain), nil
+	}
+
+	clusterID := ""
+	if strings.Contains(hostTemplate, clusterIDPlaceholder) {
+		infra, err := c.infraLister.Get("cluster")
+		if err != nil {
+			return "", fmt.Errorf("unable to get the cluster infrastructure config: %w", err)
+		}
+		clusterID = infra.Status.InfrastructureName
+	}
+
+	return renderRouteHost(hostTemplate, clusterID, ingressDomain)
+}
+
+func (c *customRouteController) getOAuthRouteAndSecretName(ingressConfig *configv1.Ingress, defaultHost string) (*routev1.Route, string, []error) {
 	route := resourceread.ReadRouteV1OrDie(assets.MustAsset("oauth-openshift/route.yaml"))
 	// set defaults
-	route.Spec.Host = "oauth-openshift." + ingressConfig.Spec.Domain // mimic the behavior of subdomain
+	route.Spec.Host = defaultHost
 	secretName := ""
 
 	// check if a user is overriding route defaults
 	if componentRoute := common.GetComponentRouteSpec(ingressConfig, OAuthComponentRouteNamespace, OAuthComponentRouteName); componentRoute != nil {
 		var errors []error
-		// Check if the provided secret is valid
-		secretName = componentRoute.ServingCertKeyPairSecret.Name
-		if err := c.validateCustomTLSSecret(secretName); err != nil {
-			errors = append(errors, err)
-		}
-
 		// Check if the provided hostname is valid
 		hostname := string(componentRoute.Hostname)
 		if _, err := url.Parse(hostname); err != nil {
 			errors = append(errors, err)
 		}
 
+		// Check if the provided secret is valid for the hostname
+		secretName = componentRoute.ServingCertKeyPairSecret.Name
+		if err := c.validateCustomTLSSecret(secretName, hostname); err != nil {
+			errors = append(errors, err)
+		}
+
 		if errors != nil {
 			return nil, "", errors
 		}
@@ -158,10 +237,14 @@ func (c *customRouteController) getOAuthRouteAndSecretName(ingressConfig *config
 		route.Spec.Host = hostname
 	}
 
+	if err := validateRouteHost(route.Spec.Host); err != nil {
+		return nil, "", []error{err}
+	}
+
 	return route, secretName, nil
 }
 
-func (c *customRouteController) validateCustomTLSSecret(secretName string) error {
+func (c *customRouteController) validateCustomTLSSecret(secretName, hostname string) error {
 	if secretName != "" {
 		secret, err := c.secretLister.Secrets("openshift-config").Get(secretName)
 		if err != nil {
@@ -179,8 +262,10 @@ func (c *customRouteController) validateCustomTLSSecret(secretName string) error
 		certData, ok := secret.Data[corev1.TLSCertKey]
 		if !ok {
 			errors = append(errors, fmt.Errorf("custom route secret must include key %s", corev1.TLSCertKey))
-		} else {
-			errors = append(errors, datasync.ValidateServerCert(certData)...)
+		} else if certErrs := datasync.ValidateServerCert(certData); len(certErrs) > 0 {
+			errors = append(errors, certErrs...)
+		} else if err := validateCertificateHostname(certData, hostname); err != nil {
+			errors = append(errors, err)
 		}
 
 		if len(errors) != 0 {
@@ -190,14 +275,13 @@ func (c *customRouteController) validateCustomTLSSecret(secretName string) error
 	return nil
 }
 
-func (c *customRouteController) applyRoute(ctx context.Context, expectedRoute *routev1.Route) error {
+func (c *customRouteController) applyRoute(ctx context.Context, expectedRoute *routev1.Route) (*routev1.Route, error) {
 	route, err := c.routeClient.Get(ctx, "oauth-openshift", metav1.GetOptions{})
 	if errors.IsNotFound(err) {
-		_, err = c.routeClient.Create(ctx, expectedRoute, metav1.CreateOptions{})
-		return err
+		return c.routeClient.Create(ctx, expectedRoute, metav1.CreateOptions{})
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// assume it is unsafe to mutate route in case we go to a shared informer in the future
@@ -209,31 +293,37 @@ func (c *customRouteController) applyRoute(ctx context.Context, expectedRoute *r
 	if *modified || !equality.Semantic.DeepEqual(existingCopy.Spec, expectedRoute.Spec) {
 		// be careful not to print route.spec as it many contain secrets
 		existingCopy.Spec = expectedRoute.Spec
-		_, err = c.routeClient.Update(ctx, existingCopy, metav1.UpdateOptions{})
-		if err != nil {
-			return err
-		}
+		return c.routeClient.Update(ctx, existingCopy, metav1.UpdateOptions{})
 	}
 
-	return nil
+	return route, nil
 }
 
-func (c *customRouteController) updateIngressConfigStatus(ctx context.Context, ingressConfig *configv1.Ingress, customRouteErrors []error) error {
-	// update ingressConfig status
-	route, err := c.routeLister.Routes("openshift-authentication").Get("oauth-openshift")
+// validateCertificateHostname checks that the serving certificate, the first one in the
+// chain, is valid for the hostname the route gets served at so that clients do not get
+// presented a certificate for another host
+func validateCertificateHostname(certData []byte, hostname string) error {
+	certs, err := certutil.ParseCertsPEM(certData)
 	if err != nil {
 		return err
 	}
+	if err := certs[0].VerifyHostname(hostname); err != nil {
+		return fmt.Errorf("certificate is not valid for the hostname %q: %v", hostname, err)
+	}
+	return nil
+}
 
+func (c *customRouteController) updateIngressConfigStatus(ctx context.Context, ingressConfig *configv1.Ingress, defaultHost string, route *routev1.Route, healthPath string, customRouteErrors []error) error {
+	// update ingressConfig status
 	componentRoute := applyconfigv1.ComponentRouteStatus().
 		WithNamespace(c.componentRoute.Namespace).
 		WithName(c.componentRoute.Name).
-		WithDefaultHostname(configv1.Hostname("oauth-openshift." + ingressConfig.Spec.Domain)).
+		WithDefaultHostname(configv1.Hostname(defaultHost)).
 		WithCurrentHostnames(configv1.Hostname(route.Spec.Host)).
 		WithConsumingUsers("system:serviceaccount:oauth-openshift:authentication-operator").
 		WithRelatedObjects(
 			applyconfigv1.ObjectReference().
-				WithNamespace("openshift-authentication").
+				WithNamespace(common.TargetNamespace).
 				WithName("oauth-openshift").
 				WithGroup(routev1.GroupName).
 				WithResource("routes"),
@@ -243,7 +333,10 @@ func (c *customRouteController) updateIngressConfigStatus(ctx context.Context, i
 	if newConditions == nil {
 		newConditions = checkIngressURI(ingressConfig, route)
 		if newConditions == nil {
-			newConditions = checkRouteAvailablity(c.secretLister, ingressConfig, route)
+			newConditions = checkRouteServingCert(c.secretLister, ingressConfig, route)
+		}
+		if newConditions == nil {
+			newConditions = checkRouteAvailablity(c.secretLister, ingressConfig, route, healthPath)
 		}
 	}
 	newConditions = ensureDefaultConditions(newConditions)
@@ -263,7 +356,7 @@ func (c *customRouteController) updateIngressConfigStatus(ctx context.Context, i
 	componentRoute.WithConditions(newConditions...)
 
 	ingressStatus := applyconfigv1.Ingress(ingressConfig.Name).WithStatus(applyconfigv1.IngressStatus().WithComponentRoutes(componentRoute))
-	_, err = c.ingressClient.ApplyStatus(ctx, ingressStatus, c.forceApply())
+	_, err := c.ingressClient.ApplyStatus(ctx, ingressStatus, c.forceApply())
 	return err
 }
 
@@ -0,0 +1,115 @@
+package customroute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+)
+
+// fakeRouteClient keeps the routes of a single namespace in memory, the calls
+// not used by the controller panic through the nil embedded interface
+type fakeRouteClient struct {
+	routeclient.RouteInterface
+	routes map[string]*routev1.Route
+}
+
+func (f *fakeRouteClient) Get(_ context.Context, name string, _ metav1.GetOptions) (*routev1.Route, error) {
+	route, ok := f.routes[name]
+	if !ok {
+		return nil, errors.NewNotFound(routev1.Resource("routes"), name)
+	}
+	return route.DeepCopy(), nil
+}
+
+func (f *fakeRouteClient) Create(_ context.Context, route *routev1.Route, _ metav1.CreateOptions) (*routev1.Route, error) {
+	if _, ok := f.routes[route.Name]; ok {
+		return nil, errors.NewAlreadyExists(routev1.Resource("routes"), route.Name)
+	}
+	f.routes[route.Name] = route.DeepCopy()
+	return route.DeepCopy(), nil
+}
+
+func (f *fakeRouteClient) Update(_ context.Context, route *routev1.Route, _ metav1.UpdateOptions) (*routev1.Route, error) {
+	if _, ok := f.routes[route.Name]; !ok {
+		return nil, errors.NewNotFound(routev1.Resource("routes"), route.Name)
+	}
+	f.routes[route.Name] = route.DeepCopy()
+	return route.DeepCopy(), nil
+}
+
+func (f *fakeRouteClient) Delete(_ context.Context, name string, _ metav1.DeleteOptions) error {
+	if _, ok := f.routes[name]; !ok {
+		return errors.NewNotFound(routev1.Resource("routes"), name)
+	}
+	delete(f.routes, name)
+	return nil
+}
+
+func TestApplyRouteRecreatesDeletedRoute(t *testing.T) {
+	ctx := context.Background()
+	routeClient := &fakeRouteClient{routes: map[string]*routev1.Route{}}
+	c := &customRouteController{routeClient: routeClient}
+
+	expectedRoute, _, errs := c.getOAuthRouteAndSecretName(&configv1.Ingress{}, defaultRouteHost("apps.example.com"))
+	require.Empty(t, errs)
+
+	route, err := c.applyRoute(ctx, expectedRoute)
+	require.NoError(t, err)
+	require.Equal(t, "oauth-openshift.apps.example.com", route.Spec.Host)
+
+	require.NoError(t, routeClient.Delete(ctx, "oauth-openshift", metav1.DeleteOptions{}))
+
+	// the sync triggered by the delete event must bring the route back and
+	// hand it over for the status update without waiting for the lister
+	route, err = c.applyRoute(ctx, expectedRoute)
+	require.NoError(t, err)
+	require.Equal(t, "oauth-openshift.apps.example.com", route.Spec.Host)
+
+	recreated, err := routeClient.Get(ctx, "oauth-openshift", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, expectedRoute.Spec, recreated.Spec)
+}
+
+func TestValidateCertificateHostname(t *testing.T) {
+	certData, _, err := certutil.GenerateSelfSignedCertKey("oauth.example.com", nil, []string{"*.login.example.com"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		hostname      string
+		expectedError bool
+	}{
+		{
+			name:     "common name matches",
+			hostname: "oauth.example.com",
+		},
+		{
+			name:     "wildcard SAN matches",
+			hostname: "oauth.login.example.com",
+		},
+		{
+			name:          "different host",
+			hostname:      "oauth-openshift.apps.example.com",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCertificateHostname(certData, tt.hostname)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
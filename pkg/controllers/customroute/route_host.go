@@ -0,0 +1,50 @@
+package customroute
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// the placeholders the route host template may use
+	clusterIDPlaceholder     = "{cluster-id}"
+	ingressDomainPlaceholder = "{ingress-domain}"
+)
+
+// defaultRouteHost mimics the behavior of the route subdomain
+func defaultRouteHost(ingressDomain string) string {
+	return "oauth-openshift." + ingressDomain
+}
+
+// renderRouteHost replaces the placeholders of the route host template, the resulting host
+// is validated along with the rest of the route
+func renderRouteHost(hostTemplate, clusterID, ingressDomain string) (string, error) {
+	if strings.Contains(hostTemplate, clusterIDPlaceholder) && len(clusterID) == 0 {
+		return "", fmt.Errorf("route host template %q uses %s but the cluster has no infrastructure name", hostTemplate, clusterIDPlaceholder)
+	}
+
+	host := strings.NewReplacer(
+		clusterIDPlaceholder, clusterID,
+		ingressDomainPlaceholder, ingressDomain,
+	).Replace(hostTemplate)
+	if strings.ContainsAny(host, "{}") {
+		return "", fmt.Errorf("route host template %q contains unknown placeholders, only %s and %s are supported", hostTemplate, clusterIDPlaceholder, ingressDomainPlaceholder)
+	}
+	return host, nil
+}
+
+// validateRouteHost checks that the route host is a DNS name clients can resolve, whether
+// the serving certificate covers it is reported by checkRouteServingCert
+func validateRouteHost(host string) error {
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+		return fmt.Errorf("route host %q is not a valid DNS name: %s", host, strings.Join(errs, ", "))
+	}
+	for _, label := range strings.Split(host, ".") {
+		if errs := validation.IsDNS1123Label(label); len(errs) > 0 {
+			return fmt.Errorf("route host %q is not a valid DNS name, label %q: %s", host, label, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package customroute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+func TestValidateRouteHost(t *testing.T) {
+	longLabel := strings.Repeat("a", 64)
+	longDomain := strings.TrimSuffix(strings.Repeat(strings.Repeat("b", 60)+".", 4), ".") + ".example.com"
+
+	tests := []struct {
+		name          string
+		host          string
+		expectedError string
+	}{
+		{
+			name: "default host",
+			host: "oauth-openshift.apps.example.com",
+		},
+		{
+			name:          "default host on a long domain",
+			host:          "oauth-openshift." + longDomain,
+			expectedError: "not a valid DNS name",
+		},
+		{
+			name:          "label too long",
+			host:          longLabel + ".apps.example.com",
+			expectedError: "label",
+		},
+		{
+			name: "custom host outside the ingress domain",
+			host: "login.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRouteHost(tt.host)
+			if len(tt.expectedError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestGetDefaultRouteHost(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     string
+		infra         *configv1.Infrastructure
+		want          string
+		expectedError string
+	}{
+		{
+			name: "no template",
+			want: "oauth-openshift.apps.example.com",
+		},
+		{
+			name:      "ingress domain only",
+			overrides: `{"oauthServerRoute": {"hostTemplate": "login.{ingress-domain}"}}`,
+			want:      "login.apps.example.com",
+		},
+		{
+			name:      "cluster id",
+			overrides: `{"oauthServerRoute": {"hostTemplate": "oauth-{cluster-id}.{ingress-domain}"}}`,
+			infra: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{InfrastructureName: "mycluster-x7k2p"},
+			},
+			want: "oauth-mycluster-x7k2p.apps.example.com",
+		},
+		{
+			name:          "cluster id without the infrastructure config",
+			overrides:     `{"oauthServerRoute": {"hostTemplate": "oauth-{cluster-id}.{ingress-domain}"}}`,
+			expectedError: "infrastructure",
+		},
+		{
+			name:      "cluster id without an infrastructure name",
+			overrides: `{"oauthServerRoute": {"hostTemplate": "oauth-{cluster-id}.{ingress-domain}"}}`,
+			infra: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			},
+			expectedError: "no infrastructure name",
+		},
+		{
+			name:          "unknown placeholder",
+			overrides:     `{"oauthServerRoute": {"hostTemplate": "oauth-{region}.{ingress-domain}"}}`,
+			expectedError: "unknown placeholders",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &operatorv1.OperatorSpec{}
+			if len(tt.overrides) > 0 {
+				spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tt.overrides)}
+			}
+			infraIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tt.infra != nil {
+				require.NoError(t, infraIndexer.Add(tt.infra))
+			}
+			c := &customRouteController{infraLister: configlistersv1.NewInfrastructureLister(infraIndexer)}
+
+			routeOverrides, err := common.GetRouteOverrides(spec)
+			require.NoError(t, err)
+			got, err := c.getDefaultRouteHost(routeOverrides.HostTemplate, "apps.example.com")
+			if len(tt.expectedError) > 0 {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package deployment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// configMapVersion returns the value that is tracked for the config map in the
+// rollout hash, either its resource version or the hash of its data
+func configMapVersion(cm *corev1.ConfigMap, rolloutTrigger string) string {
+	if rolloutTrigger != rolloutTriggerContentHash {
+		return cm.ResourceVersion
+	}
+
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = v
+	}
+	return contentHash(data)
+}
+
+// secretVersion returns the value that is tracked for the secret in the
+// rollout hash, either its resource version or the hash of its data
+func secretVersion(secret *corev1.Secret, rolloutTrigger string) string {
+	if rolloutTrigger != rolloutTriggerContentHash {
+		return secret.ResourceVersion
+	}
+	return contentHash(secret.Data)
+}
+
+// proxyVersion returns the value that is tracked for the cluster proxy in the
+// rollout hash, either its resource version or the hash of the observed proxy
+// settings that end up in the oauth-server environment
+func proxyVersion(proxy *configv1.Proxy, rolloutTrigger string) string {
+	if rolloutTrigger != rolloutTriggerContentHash {
+		return proxy.ResourceVersion
+	}
+	return contentHash(map[string][]byte{
+		"httpProxy":  []byte(proxy.Status.HTTPProxy),
+		"httpsProxy": []byte(proxy.Status.HTTPSProxy),
+		"noProxy":    []byte(proxy.Status.NoProxy),
+	})
+}
+
+func contentHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hasher := sha256.New()
+	for _, k := range keys {
+		// length-prefix the entries through JSON so that moving bytes between
+		// a key and its value changes the hash
+		entry, _ := json.Marshal([]interface{}{k, data[k]})
+		hasher.Write(entry)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
@@ -0,0 +1,60 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestContentHashVersions(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "v4-0-config-system-cliconfig", ResourceVersion: "1"},
+		Data:       map[string]string{"v4-0-config-system-cliconfig": "config"},
+	}
+	// a metadata-only update bumps the resource version
+	cmMetadataUpdated := cm.DeepCopy()
+	cmMetadataUpdated.ResourceVersion = "2"
+	cmMetadataUpdated.Annotations = map[string]string{"unrelated": "change"}
+	cmDataUpdated := cm.DeepCopy()
+	cmDataUpdated.ResourceVersion = "3"
+	cmDataUpdated.Data["v4-0-config-system-cliconfig"] = "new config"
+
+	require.NotEqual(t, configMapVersion(cm, rolloutTriggerResourceVersion), configMapVersion(cmMetadataUpdated, rolloutTriggerResourceVersion))
+	require.Equal(t, configMapVersion(cm, rolloutTriggerContentHash), configMapVersion(cmMetadataUpdated, rolloutTriggerContentHash))
+	require.NotEqual(t, configMapVersion(cm, rolloutTriggerContentHash), configMapVersion(cmDataUpdated, rolloutTriggerContentHash))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "v4-0-config-system-session", ResourceVersion: "1"},
+		Data:       map[string][]byte{"ab": []byte("c")},
+	}
+	secretMetadataUpdated := secret.DeepCopy()
+	secretMetadataUpdated.ResourceVersion = "2"
+	// moving bytes between the key and the value is a change of content
+	secretDataShifted := secret.DeepCopy()
+	secretDataShifted.Data = map[string][]byte{"a": []byte("bc")}
+
+	require.Equal(t, secretVersion(secret, rolloutTriggerContentHash), secretVersion(secretMetadataUpdated, rolloutTriggerContentHash))
+	require.NotEqual(t, secretVersion(secret, rolloutTriggerContentHash), secretVersion(secretDataShifted, rolloutTriggerContentHash))
+
+	proxy := &configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", ResourceVersion: "1"},
+		Spec:       configv1.ProxySpec{HTTPProxy: "http://proxy:3128"},
+		Status:     configv1.ProxyStatus{HTTPProxy: "http://proxy:3128"},
+	}
+	// the spec is not used by the oauth-server until it gets observed into the status
+	proxySpecUpdated := proxy.DeepCopy()
+	proxySpecUpdated.ResourceVersion = "2"
+	proxySpecUpdated.Spec.HTTPProxy = "http://other-proxy:3128"
+	proxyStatusUpdated := proxySpecUpdated.DeepCopy()
+	proxyStatusUpdated.ResourceVersion = "3"
+	proxyStatusUpdated.Status.HTTPProxy = "http://other-proxy:3128"
+
+	require.Equal(t, proxyVersion(proxy, rolloutTriggerContentHash), proxyVersion(proxySpecUpdated, rolloutTriggerContentHash))
+	require.NotEqual(t, proxyVersion(proxy, rolloutTriggerContentHash), proxyVersion(proxyStatusUpdated, rolloutTriggerContentHash))
+	require.Equal(t, "3", proxyVersion(proxyStatusUpdated, rolloutTriggerResourceVersion))
+}
@@ -26,15 +26,26 @@ import (
 	"github.com/openshift/cluster-authentication-operator/pkg/operator/datasync"
 )
 
+// deploymentVersionHashKey carries the hash of the tracked resource versions, it's set
+// on both the deployment and its pod template so that the two can be compared
+const deploymentVersionHashKey = "operator.openshift.io/rvs-hash"
+
 func getOAuthServerDeployment(
 	operatorConfig *operatorv1.Authentication,
 	proxyConfig *configv1.Proxy,
+	overrides *deploymentOverrides,
 	bootstrapUserExists bool,
 	resourceVersions ...string,
 ) (*appsv1.Deployment, error) {
 	// load deployment
 	deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
 
+	sessionSecretMode, err := getSessionSecretMode(&deployment.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+	resourceVersions = append(resourceVersions, fmt.Sprintf("sessionSecretMode:%#o", sessionSecretMode))
+
 	// force redeploy when any associated resource changes
 	// we use a hash to prevent this value from growing indefinitely
 	// need to sort first in order to get a stable array
@@ -46,12 +57,12 @@ func getOAuthServerDeployment(
 	if deployment.Annotations == nil {
 		deployment.Annotations = map[string]string{}
 	}
-	deployment.Annotations["operator.openshift.io/rvs-hash"] = rvsHashStr
+	deployment.Annotations[deploymentVersionHashKey] = rvsHashStr
 
 	if deployment.Spec.Template.Annotations == nil {
 		deployment.Spec.Template.Annotations = map[string]string{}
 	}
-	deployment.Spec.Template.Annotations["operator.openshift.io/rvs-hash"] = rvsHashStr
+	deployment.Spec.Template.Annotations[deploymentVersionHashKey] = rvsHashStr
 
 	// Ensure a rollout when the bootstrap user goes away
 	if bootstrapUserExists {
@@ -106,6 +117,9 @@ func getOAuthServerDeployment(
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse raw server arguments: %w", err)
 	}
+	if err := overrides.setAccessLogArguments(args); err != nil {
+		return nil, err
+	}
 
 	container.Args[0] = strings.Replace(
 		container.Args[0],
@@ -117,6 +131,24 @@ func getOAuthServerDeployment(
 	return deployment, nil
 }
 
+// getSessionSecretMode returns the file mode of the mounted session secret and
+// fails if the session signing keys would be readable by others
+func getSessionSecretMode(podSpec *corev1.PodSpec) (int32, error) {
+	for _, volume := range podSpec.Volumes {
+		if volume.Name != "v4-0-config-system-session" {
+			continue
+		}
+		if volume.Secret == nil || volume.Secret.DefaultMode == nil {
+			return 0, fmt.Errorf("the session secret volume %q must set a restrictive defaultMode", volume.Name)
+		}
+		if mode := *volume.Secret.DefaultMode; mode&^0440 != 0 {
+			return 0, fmt.Errorf("the session secret volume %q has mode %#o, at most %#o is allowed", volume.Name, mode, 0440)
+		}
+		return *volume.Secret.DefaultMode, nil
+	}
+	return 0, fmt.Errorf("the session secret volume %q is missing", "v4-0-config-system-session")
+}
+
 func getSyncDataFromOperatorConfig(observedConfig []byte) (*datasync.ConfigSyncData, error) {
 	var configDeserialized map[string]interface{}
 	if err := yaml.Unmarshal(observedConfig, &configDeserialized); err != nil {
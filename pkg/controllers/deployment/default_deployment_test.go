@@ -0,0 +1,75 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	utilpointer "k8s.io/utils/pointer"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/operator/assets"
+)
+
+func TestGetSessionSecretMode(t *testing.T) {
+	sessionVolume := func(mode *int32) corev1.Volume {
+		return corev1.Volume{
+			Name: "v4-0-config-system-session",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "v4-0-config-system-session", DefaultMode: mode},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		volumes       []corev1.Volume
+		want          int32
+		expectedError bool
+	}{
+		{
+			name:    "owner read-only",
+			volumes: []corev1.Volume{sessionVolume(utilpointer.Int32Ptr(0400))},
+			want:    0400,
+		},
+		{
+			name:    "owner and group read-only",
+			volumes: []corev1.Volume{sessionVolume(utilpointer.Int32Ptr(0440))},
+			want:    0440,
+		},
+		{
+			name:          "world-readable",
+			volumes:       []corev1.Volume{sessionVolume(utilpointer.Int32Ptr(0644))},
+			expectedError: true,
+		},
+		{
+			name:          "default mode unset",
+			volumes:       []corev1.Volume{sessionVolume(nil)},
+			expectedError: true,
+		},
+		{
+			name:          "missing volume",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getSessionSecretMode(&corev1.PodSpec{Volumes: tt.volumes})
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("default deployment", func(t *testing.T) {
+		deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+		got, err := getSessionSecretMode(&deployment.Spec.Template.Spec)
+		require.NoError(t, err)
+		require.Equal(t, int32(0440), got)
+	})
+}
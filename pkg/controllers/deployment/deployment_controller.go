@@ -4,21 +4,27 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	configv1client "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
 	configinformer "github.com/openshift/client-go/config/informers/externalversions"
 	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
@@ -34,10 +40,24 @@ import (
 	"github.com/openshift/library-go/pkg/operator/status"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	"github.com/openshift/library-go/pkg/route/routeapihelpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 )
 
 var _ workload.Delegate = &oauthServerDeploymentSyncer{}
 
+// forceRolloutAnnotation can be set on the operator config to restart the oauth-server,
+// the pods are rolled out once for every new value of the annotation and not when it is
+// removed
+const forceRolloutAnnotation = "authentication.operator.openshift.io/force-rollout"
+
+const (
+	// trustedCABundleName is the config map the cluster trust bundle together with
+	// the trusted CA of the cluster proxy gets injected into
+	trustedCABundleName = "v4-0-config-system-trusted-ca-bundle"
+	trustedCABundleKey  = "ca-bundle.crt"
+)
+
 // nodeCountFunction a function to return count of nodes
 type nodeCountFunc func(nodeSelector map[string]string) (*int32, error)
 
@@ -57,14 +77,26 @@ type oauthServerDeploymentSyncer struct {
 	deployments appsv1client.DeploymentsGetter
 	auth        operatorv1client.AuthenticationsGetter
 
-	configMapLister corev1listers.ConfigMapLister
-	secretLister    corev1listers.SecretLister
-	podsLister      corev1listers.PodLister
-	proxyLister     configv1listers.ProxyLister
-	routeLister     routev1listers.RouteLister
+	deploymentLister appsv1listers.DeploymentLister
+	configMapLister  corev1listers.ConfigMapLister
+	secretLister     corev1listers.SecretLister
+	podsLister       corev1listers.PodLister
+	serviceLister    corev1listers.ServiceLister
+	nodeLister       corev1listers.NodeLister
+	proxyLister      configv1listers.ProxyLister
+	infraLister      configv1listers.InfrastructureLister
+	routeLister      routev1listers.RouteLister
+	// openshiftConfigConfigMapLister lists the config maps in openshift-config, where the
+	// trusted CA of the cluster proxy lives
+	openshiftConfigConfigMapLister corev1listers.ConfigMapLister
 
 	bootstrapUserDataGetter    bootstrap.BootstrapUserDataGetter
 	bootstrapUserChangeRollOut bool
+
+	// versionGetter holds the operand versions that were reported as fully rolled out
+	versionGetter status.VersionGetter
+	// targetVersion is the oauth-server version this operator rolls out
+	targetVersion string
 }
 
 func NewOAuthServerWorkloadController(
@@ -81,8 +113,9 @@ func NewOAuthServerWorkloadController(
 	eventsRecorder events.Recorder,
 	versionRecorder status.VersionGetter,
 	kubeInformersForTargetNamespace informers.SharedInformerFactory,
+	openshiftConfigConfigMapInformer coreinformers.ConfigMapInformer,
 ) factory.Controller {
-	targetNS := "openshift-authentication"
+	targetNS := common.TargetNamespace
 
 	oauthDeploymentSyncer := &oauthServerDeploymentSyncer{
 		operatorClient: operatorClient,
@@ -93,13 +126,22 @@ func NewOAuthServerWorkloadController(
 		deployments: kubeClient.AppsV1(),
 		auth:        authOperatorGetter,
 
-		configMapLister: kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Lister(),
-		secretLister:    kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
-		podsLister:      kubeInformersForTargetNamespace.Core().V1().Pods().Lister(),
-		proxyLister:     configInformers.Config().V1().Proxies().Lister(),
-		routeLister:     routeInformersForTargetNamespace.Route().V1().Routes().Lister(),
+		deploymentLister: kubeInformersForTargetNamespace.Apps().V1().Deployments().Lister(),
+		configMapLister:  kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Lister(),
+		secretLister:     kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
+		podsLister:       kubeInformersForTargetNamespace.Core().V1().Pods().Lister(),
+		serviceLister:    kubeInformersForTargetNamespace.Core().V1().Services().Lister(),
+		nodeLister:       nodeInformer.Lister(),
+		proxyLister:      configInformers.Config().V1().Proxies().Lister(),
+		infraLister:      configInformers.Config().V1().Infrastructures().Lister(),
+		routeLister:      routeInformersForTargetNamespace.Route().V1().Routes().Lister(),
+
+		openshiftConfigConfigMapLister: openshiftConfigConfigMapInformer.Lister(),
 
 		bootstrapUserDataGetter: bootstrapUserDataGetter,
+
+		versionGetter: versionRecorder,
+		targetVersion: os.Getenv("OPERAND_OAUTH_SERVER_IMAGE_VERSION"),
 	}
 
 	if userExists, err := oauthDeploymentSyncer.bootstrapUserDataGetter.IsEnabled(); err != nil {
@@ -113,7 +155,7 @@ func NewOAuthServerWorkloadController(
 		"OAuthServer",
 		"cluster-authentication-operator",
 		targetNS,
-		os.Getenv("OPERAND_OAUTH_SERVER_IMAGE_VERSION"),
+		oauthDeploymentSyncer.targetVersion,
 		"",
 		"OAuthServer",
 		operatorClient,
@@ -122,13 +164,16 @@ func NewOAuthServerWorkloadController(
 		[]factory.Informer{
 			configInformers.Config().V1().Ingresses().Informer(),
 			configInformers.Config().V1().Proxies().Informer(),
+			configInformers.Config().V1().Infrastructures().Informer(),
 			nodeInformer.Informer(),
+			openshiftConfigConfigMapInformer.Informer(),
 		},
 		[]factory.Informer{
 			kubeInformersForTargetNamespace.Apps().V1().Deployments().Informer(),
 			kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Informer(),
 			kubeInformersForTargetNamespace.Core().V1().Secrets().Informer(),
 			kubeInformersForTargetNamespace.Core().V1().Pods().Informer(),
+			kubeInformersForTargetNamespace.Core().V1().Services().Informer(),
 			kubeInformersForTargetNamespace.Core().V1().Namespaces().Informer(),
 			routeInformersForTargetNamespace.Route().V1().Routes().Informer(),
 		},
@@ -140,7 +185,7 @@ func NewOAuthServerWorkloadController(
 }
 
 func (c *oauthServerDeploymentSyncer) PreconditionFulfilled(_ context.Context) (bool, error) {
-	route, err := c.routeLister.Routes("openshift-authentication").Get("oauth-openshift")
+	route, err := c.routeLister.Routes(common.TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		return false, fmt.Errorf("waiting for the oauth-openshift route to appear: %w", err)
 	}
@@ -160,6 +205,21 @@ func (c *oauthServerDeploymentSyncer) Sync(ctx context.Context, syncContext fact
 		return nil, false, append(errs, err)
 	}
 
+	// keep reporting on the current deployment but don't touch it while paused
+	if common.IsReconciliationPaused(operatorConfig) {
+		deployment, err := c.deploymentLister.Deployments(common.TargetNamespace).Get("oauth-openshift")
+		if err != nil {
+			return nil, false, append(errs, err)
+		}
+		if _, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient,
+			v1helpers.UpdateConditionFn(deploymentReplicasCondition(deployment)),
+			v1helpers.UpdateConditionFn(operandVersionCondition(c.versionGetter.GetVersions()["oauth-openshift"], c.targetVersion)),
+		); err != nil {
+			errs = append(errs, err)
+		}
+		return deployment, true, errs
+	}
+
 	proxyConfig, err := c.getProxyConfig()
 	if err != nil {
 		return nil, false, append(errs, err)
@@ -173,34 +233,49 @@ func (c *oauthServerDeploymentSyncer) Sync(ctx context.Context, syncContext fact
 	// TODO move this hash from deployment meta to operatorConfig.status.generations.[...].hash
 	resourceVersions := []string{}
 
+	overrides, err := getDeploymentOverrides(&operatorConfig.Spec.OperatorSpec)
+	if err != nil {
+		return nil, false, append(errs, err)
+	}
+	resourceVersions = append(resourceVersions, overrides.rolloutTriggers()...)
+
 	if len(proxyConfig.Name) > 0 {
-		resourceVersions = append(resourceVersions, "proxy:"+proxyConfig.Name+":"+proxyConfig.ResourceVersion)
+		resourceVersions = append(resourceVersions, "proxy:"+proxyConfig.Name+":"+proxyVersion(proxyConfig, overrides.RolloutTrigger))
 	}
 
-	configResourceVersions, err := c.getConfigResourceVersions()
+	configResourceVersions, err := c.getConfigResourceVersions(overrides.RolloutTrigger)
 	if err != nil {
 		return nil, false, append(errs, err)
 	}
 
 	resourceVersions = append(resourceVersions, configResourceVersions...)
 
-	// Determine whether the bootstrap user has been deleted so that
-	// detail can be used in computing the deployment.
-	if c.bootstrapUserChangeRollOut {
-		if userExists, err := c.bootstrapUserDataGetter.IsEnabled(); err != nil {
-			klog.Warningf("unable to determine the state of bootstrap user: %v", err)
-		} else {
-			c.bootstrapUserChangeRollOut = userExists
-		}
+	nodeSelector, err := getControlPlaneNodeSelector(c.nodeLister)
+	if err != nil {
+		return nil, false, append(errs, err)
 	}
+	resourceVersions = append(resourceVersions, nodeSelectorRolloutTrigger(nodeSelector))
 
-	// deployment, have RV of all resources
-	expectedDeployment, err := getOAuthServerDeployment(operatorConfig, proxyConfig, c.bootstrapUserChangeRollOut, resourceVersions...)
+	topologySpreadConstraints, err := getTopologySpreadConstraints(c.infraLister)
 	if err != nil {
 		return nil, false, append(errs, err)
 	}
+	resourceVersions = append(resourceVersions, topologySpreadRolloutTrigger(topologySpreadConstraints))
 
-	if _, err := c.secretLister.Secrets("openshift-authentication").Get("v4-0-config-system-custom-router-certs"); err == nil {
+	// deployment, have RV of all resources
+	expectedDeployment, err := getOAuthServerDeployment(operatorConfig, proxyConfig, overrides, c.isBootstrapUserEnabled(), resourceVersions...)
+	if err != nil {
+		return nil, false, append(errs, err)
+	}
+	expectedDeployment.Spec.Template.Spec.NodeSelector = nodeSelector
+	expectedDeployment.Spec.Template.Spec.TopologySpreadConstraints = topologySpreadConstraints
+	expectedDeployment.Spec.Template.Spec.Containers[0].Env = append(expectedDeployment.Spec.Template.Spec.Containers[0].Env, overrides.envVars()...)
+	overrides.setStartupProbe(&expectedDeployment.Spec.Template.Spec.Containers[0])
+	overrides.setDNS(&expectedDeployment.Spec.Template.Spec)
+	overrides.setReadinessGates(&expectedDeployment.Spec.Template.Spec)
+	overrides.setServiceAccountToken(&expectedDeployment.Spec.Template.Spec)
+
+	if _, err := c.secretLister.Secrets(common.TargetNamespace).Get("v4-0-config-system-custom-router-certs"); err == nil {
 		expectedDeployment.Spec.Template.Spec.Volumes = append(expectedDeployment.Spec.Template.Spec.Volumes, corev1.Volume{
 			Name: "v4-0-config-system-custom-router-certs",
 			VolumeSource: corev1.VolumeSource{
@@ -216,11 +291,21 @@ func (c *oauthServerDeploymentSyncer) Sync(ctx context.Context, syncContext fact
 		})
 	}
 
-	err = c.ensureAtMostOnePodPerNode(&expectedDeployment.Spec, "oauth-openshift")
-	if err != nil {
-		return nil, false, append(errs, fmt.Errorf("unable to ensure at most one pod per node: %v", err))
+	if len(overrides.ImagePullSecret) > 0 {
+		if err := validateImagePullSecret(c.secretLister, overrides.ImagePullSecret); err != nil {
+			return nil, false, append(errs, err)
+		}
+		expectedDeployment.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: overrides.ImagePullSecret}}
 	}
 
+	if err := setPodAntiAffinity(&expectedDeployment.Spec, overrides.PodAntiAffinity, c.ensureAtMostOnePodPerNode); err != nil {
+		return nil, false, append(errs, err)
+	}
+	if overrides.AvoidKubeAPIServerNodes {
+		setKubeAPIServerAntiAffinity(&expectedDeployment.Spec)
+	}
+	setRolloutStrategy(&expectedDeployment.Spec, overrides.RolloutStrategy, overrides.MaxSurge)
+
 	// Set the replica count to the number of master nodes.
 	masterNodeCount, err := c.countNodes(expectedDeployment.Spec.Template.Spec.NodeSelector)
 	if err != nil {
@@ -228,6 +313,19 @@ func (c *oauthServerDeploymentSyncer) Sync(ctx context.Context, syncContext fact
 	}
 	expectedDeployment.Spec.Replicas = masterNodeCount
 
+	existingDeployment, err := c.deploymentLister.Deployments(common.TargetNamespace).Get("oauth-openshift")
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, false, append(errs, err)
+	}
+	forceRollout := getForceRolloutValue(operatorConfig.Annotations[forceRolloutAnnotation], existingDeployment)
+	if len(forceRollout) > 0 {
+		expectedDeployment.Spec.Template.Annotations[forceRolloutAnnotation] = forceRollout
+	}
+	rolloutForced := isRolloutForced(existingDeployment, forceRollout)
+	hashTampered := isVersionHashTampered(existingDeployment, expectedDeployment.Annotations[deploymentVersionHashKey])
+
+	// the metadata are merged on apply, a tampered hash is restored without a rollout
+	// as long as the pod template is unchanged
 	deployment, _, err := resourceapply.ApplyDeployment(ctx, c.deployments,
 		syncContext.Recorder(),
 		expectedDeployment,
@@ -236,10 +334,196 @@ func (c *oauthServerDeploymentSyncer) Sync(ctx context.Context, syncContext fact
 	if err != nil {
 		return nil, false, append(errs, fmt.Errorf("applying deployment of the integrated OAuth server failed: %w", err))
 	}
+	if hashTampered {
+		syncContext.Recorder().Warningf("OAuthServerVersionHashRepaired", "The %q annotation of the oauth-openshift deployment did not match the tracked resource versions, it was restored", deploymentVersionHashKey)
+	}
+	if rolloutForced {
+		syncContext.Recorder().Eventf("OAuthServerRolloutForced", "Rolling the oauth-server out as requested by the %q annotation with the value %q", forceRolloutAnnotation, forceRollout)
+	}
+
+	statusUpdates := []v1helpers.UpdateStatusFunc{
+		v1helpers.UpdateConditionFn(deploymentReplicasCondition(deployment)),
+		v1helpers.UpdateConditionFn(operandVersionCondition(c.versionGetter.GetVersions()["oauth-openshift"], c.targetVersion)),
+	}
+	// the service is applied by the static resources controller, there is nothing to compare until it exists
+	if service, err := c.serviceLister.Services(common.TargetNamespace).Get("oauth-openshift"); err == nil {
+		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(serviceSelectorCondition(service, deployment)))
+	} else if !errors.IsNotFound(err) {
+		errs = append(errs, err)
+	}
+	if pods, err := c.podsLister.Pods(common.TargetNamespace).List(labels.SelectorFromSet(deployment.Spec.Template.Labels)); err != nil {
+		errs = append(errs, err)
+	} else {
+		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(oomKilledCondition(pods, time.Now())))
+	}
+	if condition, err := c.getProxyTrustedCACondition(proxyConfig, deployment); err != nil {
+		errs = append(errs, err)
+	} else {
+		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(condition))
+	}
+	if _, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, statusUpdates...); err != nil {
+		errs = append(errs, err)
+	}
 
 	return deployment, true, errs
 }
 
+// serviceSelectorCondition degrades when the oauth-openshift service does not select the pods
+// of the oauth-server deployment, the pods would run healthy but the service would have no
+// endpoints to route the logins to
+func serviceSelectorCondition(service *corev1.Service, deployment *appsv1.Deployment) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthServerServiceSelectorDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	podLabels := labels.Set(deployment.Spec.Template.Labels)
+	switch {
+	case len(service.Spec.Selector) == 0:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SelectorMismatch"
+		condition.Message = fmt.Sprintf("service %s/%s has no selector, it does not route to the oauth-server pods", service.Namespace, service.Name)
+	case !labels.SelectorFromSet(service.Spec.Selector).Matches(podLabels):
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "SelectorMismatch"
+		condition.Message = fmt.Sprintf("the selector %q of service %s/%s does not match the oauth-server pod labels %q", labels.Set(service.Spec.Selector).String(), service.Namespace, service.Name, podLabels.String())
+	}
+	return condition
+}
+
+// oomKilledWindow is how long an OOMKill of an oauth-server container is reported after
+// it happened, the containers that were killed once and run fine since are not a problem
+const oomKilledWindow = 10 * time.Minute
+
+// oomKilledCondition degrades when any oauth-server container was OOMKilled recently, the
+// pods would otherwise only show up as restarting and not ready
+func oomKilledCondition(pods []*corev1.Pod, now time.Time) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthServerOOMKilledDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	var killed []string
+	for _, pod := range pods {
+		memoryLimits := map[string]string{}
+		for _, container := range pod.Spec.Containers {
+			if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+				memoryLimits[container.Name] = limit.String()
+			}
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated == nil || terminated.Reason != "OOMKilled" || now.Sub(terminated.FinishedAt.Time) > oomKilledWindow {
+				continue
+			}
+
+			limit := "no memory limit"
+			if memoryLimit, ok := memoryLimits[status.Name]; ok {
+				limit = "memory limit " + memoryLimit
+			}
+			killed = append(killed, fmt.Sprintf("pod %q container %q (%s, restarted %d times)", pod.Name, status.Name, limit, status.RestartCount))
+		}
+	}
+	sort.Strings(killed)
+
+	if len(killed) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ContainerOOMKilled"
+		condition.Message = fmt.Sprintf("oauth-server containers were OOMKilled: %s. Increase their memory limit or check the LimitRanges of the %s namespace and the memory pressure of the control plane nodes",
+			strings.Join(killed, ", "), common.TargetNamespace)
+	}
+	return condition
+}
+
+func (c *oauthServerDeploymentSyncer) getProxyTrustedCACondition(proxyConfig *configv1.Proxy, deployment *appsv1.Deployment) (operatorv1.OperatorCondition, error) {
+	var proxyCA, trustedCABundle *corev1.ConfigMap
+	if name := proxyConfig.Spec.TrustedCA.Name; len(name) > 0 {
+		var err error
+		if proxyCA, err = c.openshiftConfigConfigMapLister.ConfigMaps("openshift-config").Get(name); err != nil && !errors.IsNotFound(err) {
+			return operatorv1.OperatorCondition{}, err
+		}
+		if trustedCABundle, err = c.configMapLister.ConfigMaps(common.TargetNamespace).Get(trustedCABundleName); err != nil && !errors.IsNotFound(err) {
+			return operatorv1.OperatorCondition{}, err
+		}
+	}
+	return proxyTrustedCACondition(proxyConfig, proxyCA, trustedCABundle, deployment), nil
+}
+
+// proxyTrustedCACondition degrades when the oauth-server does not trust the CA the cluster
+// proxy requires, the calls to the identity providers through the proxy would fail with
+// TLS errors. The network operator injects the proxy CA into the trusted CA bundle of the
+// oauth-server, the proxyCA and trustedCABundle are nil when the config maps don't exist.
+func proxyTrustedCACondition(proxyConfig *configv1.Proxy, proxyCA, trustedCABundle *corev1.ConfigMap, deployment *appsv1.Deployment) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthServerProxyTrustedCADegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	proxyCAName := proxyConfig.Spec.TrustedCA.Name
+	if len(proxyCAName) == 0 {
+		return condition
+	}
+
+	degraded := func(reason, messageFmt string, args ...interface{}) operatorv1.OperatorCondition {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = reason
+		condition.Message = fmt.Sprintf(messageFmt, args...)
+		return condition
+	}
+
+	if proxyCA == nil {
+		return degraded("ProxyTrustedCAMissing", "the trusted CA config map openshift-config/%s of the cluster proxy does not exist", proxyCAName)
+	}
+	proxyCerts, err := certutil.ParseCertsPEM([]byte(proxyCA.Data[trustedCABundleKey]))
+	if err != nil {
+		return degraded("ProxyTrustedCAInvalid", "the %q key of the trusted CA config map openshift-config/%s of the cluster proxy does not contain valid certificates: %v", trustedCABundleKey, proxyCAName, err)
+	}
+
+	if trustedCABundle == nil {
+		return degraded("TrustedCABundleMissing", "the config map %s/%s that should carry the trusted CA openshift-config/%s of the cluster proxy does not exist", common.TargetNamespace, trustedCABundleName, proxyCAName)
+	}
+	bundleCerts, _ := certutil.ParseCertsPEM([]byte(trustedCABundle.Data[trustedCABundleKey]))
+	injected := sets.NewString()
+	for _, cert := range bundleCerts {
+		injected.Insert(string(cert.Raw))
+	}
+	for _, cert := range proxyCerts {
+		if !injected.Has(string(cert.Raw)) {
+			return degraded("ProxyTrustedCANotInjected", "the certificate %q of the trusted CA openshift-config/%s of the cluster proxy is missing from the config map %s/%s, check the network operator that injects it", cert.Subject.String(), proxyCAName, common.TargetNamespace, trustedCABundleName)
+		}
+	}
+
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.ConfigMap != nil && volume.ConfigMap.Name == trustedCABundleName {
+			return condition
+		}
+	}
+	return degraded("TrustedCABundleNotMounted", "the config map %s/%s with the trusted CA openshift-config/%s of the cluster proxy is not mounted into the oauth-server pods", common.TargetNamespace, trustedCABundleName, proxyCAName)
+}
+
+// isBootstrapUserEnabled determines whether the bootstrap user has been deleted so that
+// detail can be used in computing the deployment. The oauth-server only checks for the
+// kube-system/kubeadmin secret on startup, the deployment is rolled out once the secret
+// is gone so that the login page stops offering the kube:admin provider. The user cannot
+// come back so it is not checked anymore after that.
+func (c *oauthServerDeploymentSyncer) isBootstrapUserEnabled() bool {
+	if c.bootstrapUserChangeRollOut {
+		if userExists, err := c.bootstrapUserDataGetter.IsEnabled(); err != nil {
+			klog.Warningf("unable to determine the state of bootstrap user: %v", err)
+		} else {
+			c.bootstrapUserChangeRollOut = userExists
+		}
+	}
+	return c.bootstrapUserChangeRollOut
+}
+
 func (c *oauthServerDeploymentSyncer) getProxyConfig() (*configv1.Proxy, error) {
 	proxyConfig, err := c.proxyLister.Get("cluster")
 	if err != nil {
@@ -252,30 +536,106 @@ func (c *oauthServerDeploymentSyncer) getProxyConfig() (*configv1.Proxy, error)
 	return proxyConfig, nil
 }
 
-func (c *oauthServerDeploymentSyncer) getConfigResourceVersions() ([]string, error) {
+func (c *oauthServerDeploymentSyncer) getConfigResourceVersions(rolloutTrigger string) ([]string, error) {
 	var configRVs []string
 
-	configMaps, err := c.configMapLister.ConfigMaps("openshift-authentication").List(labels.Everything())
+	configMaps, err := c.configMapLister.ConfigMaps(common.TargetNamespace).List(labels.Everything())
 	if err != nil {
-		return nil, fmt.Errorf("unable to list configmaps in %q namespace: %v", "openshift-authentication", err)
+		return nil, fmt.Errorf("unable to list configmaps in %q namespace: %v", common.TargetNamespace, err)
 	}
 	for _, cm := range configMaps {
-		if strings.HasPrefix(cm.Name, "v4-0-config-") {
+		// the audit policy is mounted as well
+		if strings.HasPrefix(cm.Name, "v4-0-config-") || cm.Name == "audit" {
 			// prefix the RV to make it clear where it came from since each resource can be from different etcd
-			configRVs = append(configRVs, "configmaps:"+cm.Name+":"+cm.ResourceVersion)
+			configRVs = append(configRVs, "configmaps:"+cm.Name+":"+configMapVersion(cm, rolloutTrigger))
 		}
 	}
 
-	secrets, err := c.secretLister.Secrets("openshift-authentication").List(labels.Everything())
+	secrets, err := c.secretLister.Secrets(common.TargetNamespace).List(labels.Everything())
 	if err != nil {
-		return nil, fmt.Errorf("unable to list secrets in %q namespace: %v", "openshift-authentication", err)
+		return nil, fmt.Errorf("unable to list secrets in %q namespace: %v", common.TargetNamespace, err)
 	}
 	for _, secret := range secrets {
 		if strings.HasPrefix(secret.Name, "v4-0-config-") {
 			// prefix the RV to make it clear where it came from since each resource can be from different etcd
-			configRVs = append(configRVs, "secrets:"+secret.Name+":"+secret.ResourceVersion)
+			configRVs = append(configRVs, "secrets:"+secret.Name+":"+secretVersion(secret, rolloutTrigger))
 		}
 	}
 
 	return configRVs, nil
 }
+
+// deploymentReplicasCondition is an informational condition that reports how many of the
+// desired oauth-server replicas are updated and available, it is True only when all are
+func deploymentReplicasCondition(deployment *appsv1.Deployment) operatorv1.OperatorCondition {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	updated, available := deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas
+
+	condition := operatorv1.OperatorCondition{
+		Type:    "OAuthServerReplicas",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "AsExpected",
+		Message: fmt.Sprintf("desired: %d, updated: %d, available: %d", desired, updated, available),
+	}
+	if available < desired {
+		condition.Status = operatorv1.ConditionFalse
+		condition.Reason = "ReplicasUnavailable"
+	}
+	return condition
+}
+
+// operandVersionCondition reports the oauth-server version transition during an upgrade, the
+// current version is the one recorded once the deployment was fully rolled out
+func operandVersionCondition(currentVersion, targetVersion string) operatorv1.OperatorCondition {
+	if len(targetVersion) == 0 || currentVersion == targetVersion {
+		return operatorv1.OperatorCondition{
+			Type:   "OAuthServerVersionProgressing",
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+
+	if len(currentVersion) == 0 {
+		currentVersion = "none"
+	}
+	return operatorv1.OperatorCondition{
+		Type:    "OAuthServerVersionProgressing",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "OperandVersionChanging",
+		Message: fmt.Sprintf("oauth-server is moving from version %s to %s", currentVersion, targetVersion),
+	}
+}
+
+// isVersionHashTampered returns true if the resource versions hash of the deployment differs from
+// the one computed in this sync while its pod template still carries the computed one, i.e. only
+// the deployment metadata were edited. A differing pod template is a regular rollout.
+func isVersionHashTampered(existing *appsv1.Deployment, expectedHash string) bool {
+	if existing == nil {
+		return false
+	}
+	return existing.Spec.Template.Annotations[deploymentVersionHashKey] == expectedHash &&
+		existing.Annotations[deploymentVersionHashKey] != expectedHash
+}
+
+// getForceRolloutValue returns the force-rollout value the pod template carries, the last
+// requested one is kept once the annotation is removed from the operator config so that
+// the removal does not roll the pods out again
+func getForceRolloutValue(requested string, existing *appsv1.Deployment) string {
+	if len(requested) > 0 || existing == nil {
+		return requested
+	}
+	return existing.Spec.Template.Annotations[forceRolloutAnnotation]
+}
+
+// isRolloutForced returns true if the force-rollout annotation requests a rollout that
+// the existing deployment has not gone through yet
+func isRolloutForced(existing *appsv1.Deployment, forceRollout string) bool {
+	if len(forceRollout) == 0 || existing == nil {
+		// a new deployment is rolled out anyway
+		return false
+	}
+	return existing.Spec.Template.Annotations[forceRolloutAnnotation] != forceRollout
+}
@@ -0,0 +1,490 @@
+package deployment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+	utilpointer "k8s.io/utils/pointer"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	bootstrap "github.com/openshift/library-go/pkg/authentication/bootstrapauthenticator"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/operator/assets"
+)
+
+func TestDeploymentReplicasCondition(t *testing.T) {
+	tests := []struct {
+		name            string
+		replicas        *int32
+		status          appsv1.DeploymentStatus
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:            "all replicas available",
+			replicas:        utilpointer.Int32Ptr(3),
+			status:          appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 3},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "desired: 3, updated: 3, available: 3",
+		},
+		{
+			name:            "rolling out",
+			replicas:        utilpointer.Int32Ptr(3),
+			status:          appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 3},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "desired: 3, updated: 1, available: 3",
+		},
+		{
+			name:            "single replica up",
+			replicas:        utilpointer.Int32Ptr(3),
+			status:          appsv1.DeploymentStatus{UpdatedReplicas: 3, AvailableReplicas: 1},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: "desired: 3, updated: 3, available: 1",
+		},
+		{
+			name:            "replicas defaulted",
+			status:          appsv1.DeploymentStatus{},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: "desired: 1, updated: 0, available: 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := deploymentReplicasCondition(&appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: tt.replicas},
+				Status: tt.status,
+			})
+			require.Equal(t, "OAuthServerReplicas", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedMessage, condition.Message)
+		})
+	}
+}
+
+func TestOperandVersionCondition(t *testing.T) {
+	tests := []struct {
+		name            string
+		currentVersion  string
+		targetVersion   string
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "versions match",
+			currentVersion: "4.12.0",
+			targetVersion:  "4.12.0",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "upgrading",
+			currentVersion:  "4.11.5",
+			targetVersion:   "4.12.0",
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "oauth-server is moving from version 4.11.5 to 4.12.0",
+		},
+		{
+			name:            "installing",
+			targetVersion:   "4.12.0",
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "oauth-server is moving from version none to 4.12.0",
+		},
+		{
+			name:           "no target version",
+			currentVersion: "4.11.5",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := operandVersionCondition(tt.currentVersion, tt.targetVersion)
+			require.Equal(t, "OAuthServerVersionProgressing", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedMessage, condition.Message)
+		})
+	}
+}
+
+func TestIsVersionHashTampered(t *testing.T) {
+	deploymentWithHashes := func(deploymentHash, templateHash string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		if len(deploymentHash) > 0 {
+			deployment.Annotations = map[string]string{deploymentVersionHashKey: deploymentHash}
+		}
+		if len(templateHash) > 0 {
+			deployment.Spec.Template.Annotations = map[string]string{deploymentVersionHashKey: templateHash}
+		}
+		return deployment
+	}
+
+	tests := []struct {
+		name     string
+		existing *appsv1.Deployment
+		expected bool
+	}{
+		{
+			name: "no deployment yet",
+		},
+		{
+			name:     "matching hashes",
+			existing: deploymentWithHashes("abc", "abc"),
+		},
+		{
+			name:     "deployment hash edited",
+			existing: deploymentWithHashes("edited", "abc"),
+			expected: true,
+		},
+		{
+			name:     "deployment hash removed",
+			existing: deploymentWithHashes("", "abc"),
+			expected: true,
+		},
+		{
+			name:     "resource versions changed",
+			existing: deploymentWithHashes("old", "old"),
+		},
+		{
+			name:     "template hash edited",
+			existing: deploymentWithHashes("abc", "edited"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, isVersionHashTampered(tt.existing, "abc"))
+		})
+	}
+}
+
+func TestIsRolloutForced(t *testing.T) {
+	deploymentWithForceRollout := func(value string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		if len(value) > 0 {
+			deployment.Spec.Template.Annotations = map[string]string{forceRolloutAnnotation: value}
+		}
+		return deployment
+	}
+
+	tests := []struct {
+		name         string
+		existing     *appsv1.Deployment
+		forceRollout string
+		expected     bool
+	}{
+		{
+			name:     "no annotation",
+			existing: deploymentWithForceRollout(""),
+		},
+		{
+			name:         "first request",
+			existing:     deploymentWithForceRollout(""),
+			forceRollout: "2022-10-14T10:00:00Z",
+			expected:     true,
+		},
+		{
+			name:         "new request",
+			existing:     deploymentWithForceRollout("2022-10-14T10:00:00Z"),
+			forceRollout: "2022-10-15T10:00:00Z",
+			expected:     true,
+		},
+		{
+			name:         "request already rolled out",
+			existing:     deploymentWithForceRollout("2022-10-14T10:00:00Z"),
+			forceRollout: "2022-10-14T10:00:00Z",
+		},
+		{
+			name:         "no deployment yet",
+			forceRollout: "2022-10-14T10:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, isRolloutForced(tt.existing, tt.forceRollout))
+		})
+	}
+}
+
+func TestGetForceRolloutValue(t *testing.T) {
+	existing := &appsv1.Deployment{}
+	existing.Spec.Template.Annotations = map[string]string{forceRolloutAnnotation: "2022-10-14T10:00:00Z"}
+
+	tests := []struct {
+		name      string
+		requested string
+		existing  *appsv1.Deployment
+		expected  string
+	}{
+		{
+			name: "never requested",
+		},
+		{
+			name:      "first request",
+			requested: "2022-10-14T10:00:00Z",
+			expected:  "2022-10-14T10:00:00Z",
+		},
+		{
+			name:      "new request",
+			requested: "2022-10-15T10:00:00Z",
+			existing:  existing,
+			expected:  "2022-10-15T10:00:00Z",
+		},
+		{
+			name:     "annotation removed after a rollout",
+			existing: existing,
+			expected: "2022-10-14T10:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getForceRolloutValue(tt.requested, tt.existing))
+		})
+	}
+}
+
+func TestServiceSelectorCondition(t *testing.T) {
+	assetService := resourceread.ReadServiceV1OrDie(assets.MustAsset("oauth-openshift/oauth-service.yaml"))
+	assetDeployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+
+	tests := []struct {
+		name           string
+		selector       map[string]string
+		expectedStatus operatorv1.ConditionStatus
+	}{
+		{
+			// catches the service and the deployment assets drifting apart
+			name:           "assets",
+			selector:       assetService.Spec.Selector,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "selector subset of the pod labels",
+			selector:       map[string]string{"app": "oauth-openshift"},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "different value",
+			selector:       map[string]string{"app": "openshift-oauth"},
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:           "extra label",
+			selector:       map[string]string{"app": "oauth-openshift", "component": "oauth-server"},
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:           "no selector",
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := assetService.DeepCopy()
+			service.Spec.Selector = tt.selector
+
+			condition := serviceSelectorCondition(service, assetDeployment)
+			require.Equal(t, "OAuthServerServiceSelectorDegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status, condition.Message)
+		})
+	}
+}
+
+func TestProxyTrustedCACondition(t *testing.T) {
+	proxyCACert, _, err := certutil.GenerateSelfSignedCertKey("proxy-ca", nil, nil)
+	require.NoError(t, err)
+	systemCACert, _, err := certutil.GenerateSelfSignedCertKey("system-ca", nil, nil)
+	require.NoError(t, err)
+
+	proxyWithCA := &configv1.Proxy{Spec: configv1.ProxySpec{TrustedCA: configv1.ConfigMapNameReference{Name: "proxy-ca"}}}
+	proxyCA := &corev1.ConfigMap{Data: map[string]string{trustedCABundleKey: string(proxyCACert)}}
+	injectedBundle := &corev1.ConfigMap{Data: map[string]string{trustedCABundleKey: string(systemCACert) + string(proxyCACert)}}
+	assetDeployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+
+	tests := []struct {
+		name            string
+		proxy           *configv1.Proxy
+		proxyCA         *corev1.ConfigMap
+		trustedCABundle *corev1.ConfigMap
+		deployment      *appsv1.Deployment
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+	}{
+		{
+			name:           "no proxy CA",
+			proxy:          &configv1.Proxy{},
+			deployment:     &appsv1.Deployment{},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:            "proxy CA injected and mounted",
+			proxy:           proxyWithCA,
+			proxyCA:         proxyCA,
+			trustedCABundle: injectedBundle,
+			deployment:      assetDeployment,
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  "AsExpected",
+		},
+		{
+			name:           "proxy CA config map missing",
+			proxy:          proxyWithCA,
+			deployment:     assetDeployment,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ProxyTrustedCAMissing",
+		},
+		{
+			name:           "proxy CA without certificates",
+			proxy:          proxyWithCA,
+			proxyCA:        &corev1.ConfigMap{Data: map[string]string{trustedCABundleKey: "not a certificate"}},
+			deployment:     assetDeployment,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ProxyTrustedCAInvalid",
+		},
+		{
+			name:           "trusted CA bundle missing",
+			proxy:          proxyWithCA,
+			proxyCA:        proxyCA,
+			deployment:     assetDeployment,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "TrustedCABundleMissing",
+		},
+		{
+			name:            "proxy CA not injected yet",
+			proxy:           proxyWithCA,
+			proxyCA:         proxyCA,
+			trustedCABundle: &corev1.ConfigMap{Data: map[string]string{trustedCABundleKey: string(systemCACert)}},
+			deployment:      assetDeployment,
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "ProxyTrustedCANotInjected",
+		},
+		{
+			name:            "trusted CA bundle not mounted",
+			proxy:           proxyWithCA,
+			proxyCA:         proxyCA,
+			trustedCABundle: injectedBundle,
+			deployment:      &appsv1.Deployment{},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "TrustedCABundleNotMounted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := proxyTrustedCACondition(tt.proxy, tt.proxyCA, tt.trustedCABundle, tt.deployment)
+			require.Equal(t, "OAuthServerProxyTrustedCADegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status, condition.Message)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+		})
+	}
+}
+
+type fakeBootstrapUserDataGetter struct {
+	bootstrap.BootstrapUserDataGetter
+	enabled []bool
+	calls   int
+}
+
+func (f *fakeBootstrapUserDataGetter) IsEnabled() (bool, error) {
+	enabled := f.enabled[f.calls]
+	f.calls++
+	return enabled, nil
+}
+
+func TestBootstrapUserRemoval(t *testing.T) {
+	getter := &fakeBootstrapUserDataGetter{enabled: []bool{true, false, true}}
+	c := &oauthServerDeploymentSyncer{
+		bootstrapUserDataGetter:    getter,
+		bootstrapUserChangeRollOut: true,
+	}
+	operatorConfig := &operatorv1.Authentication{}
+	operatorConfig.Spec.ObservedConfig.Raw = []byte(`{"oauthServer": {}}`)
+
+	withUser, err := getOAuthServerDeployment(operatorConfig, &configv1.Proxy{}, &deploymentOverrides{}, c.isBootstrapUserEnabled())
+	require.NoError(t, err)
+	require.Equal(t, "true", withUser.Spec.Template.Annotations["operator.openshift.io/bootstrap-user-exists"])
+
+	withoutUser, err := getOAuthServerDeployment(operatorConfig, &configv1.Proxy{}, &deploymentOverrides{}, c.isBootstrapUserEnabled())
+	require.NoError(t, err)
+	require.NotContains(t, withoutUser.Spec.Template.Annotations, "operator.openshift.io/bootstrap-user-exists")
+	// the pod template changes so that the oauth-server gets rolled out without the kube:admin provider
+	require.NotEqual(t, withUser.Spec.Template.Annotations, withoutUser.Spec.Template.Annotations)
+
+	// the removal is final, the user is not looked up anymore
+	require.False(t, c.isBootstrapUserEnabled())
+	require.Equal(t, 2, getter.calls)
+}
+
+func TestOOMKilledCondition(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(name string, limit string, state, lastState corev1.ContainerState) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "oauth-openshift"}}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "oauth-openshift",
+				State:                state,
+				LastTerminationState: lastState,
+				RestartCount:         4,
+			}}},
+		}
+		if len(limit) > 0 {
+			p.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limit)}
+		}
+		return p
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	terminated := func(reason string, ago time.Duration) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: reason, FinishedAt: metav1.NewTime(now.Add(-ago))}}
+	}
+
+	tests := []struct {
+		name            string
+		pods            []*corev1.Pod
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "healthy pods",
+			pods:           []*corev1.Pod{pod("oauth-a", "", running, corev1.ContainerState{})},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "restarted for another reason",
+			pods:           []*corev1.Pod{pod("oauth-a", "", running, terminated("Error", time.Minute))},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "recently OOMKilled",
+			pods:            []*corev1.Pod{pod("oauth-a", "100Mi", running, terminated("OOMKilled", time.Minute))},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: `pod "oauth-a" container "oauth-openshift" (memory limit 100Mi, restarted 4 times)`,
+		},
+		{
+			name:            "OOMKilled right now",
+			pods:            []*corev1.Pod{pod("oauth-a", "", terminated("OOMKilled", 0), corev1.ContainerState{})},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: `pod "oauth-a" container "oauth-openshift" (no memory limit, restarted 4 times)`,
+		},
+		{
+			name:           "OOMKilled long ago",
+			pods:           []*corev1.Pod{pod("oauth-a", "100Mi", running, terminated("OOMKilled", time.Hour))},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := oomKilledCondition(tt.pods, now)
+			require.Equal(t, "OAuthServerOOMKilledDegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			if tt.expectedStatus == operatorv1.ConditionTrue {
+				require.Equal(t, "ContainerOOMKilled", condition.Reason)
+				require.Contains(t, condition.Message, tt.expectedMessage)
+			}
+		})
+	}
+}
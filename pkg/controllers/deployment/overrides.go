@@ -0,0 +1,641 @@
+package deployment
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common/arguments"
+	observeoauth "github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation/oauth"
+)
+
+const (
+	// podAntiAffinitySoft prefers to spread the oauth-server pods across nodes
+	podAntiAffinitySoft = "soft"
+	// podAntiAffinityHard never schedules two oauth-server pods on the same node
+	podAntiAffinityHard = "hard"
+
+	// rolloutTriggerResourceVersion rolls the oauth-server out whenever the resource
+	// version of any of its config resources changes
+	rolloutTriggerResourceVersion = "resourceVersion"
+	// rolloutTriggerContentHash rolls the oauth-server out only when the content
+	// of any of its config resources changes
+	rolloutTriggerContentHash = "contentHash"
+
+	// rolloutStrategySurge brings a new oauth-server pod up before an old one is
+	// taken down so that all the prior replicas keep serving during a rollout
+	rolloutStrategySurge = "surge"
+	// rolloutStrategyReplace takes an old oauth-server pod down before a new one is
+	// brought up, it's the only choice when each pod needs a node of its own
+	rolloutStrategyReplace = "replace"
+
+	// accessLogFormatJSON and accessLogFormatLegacy are the formats of the audit log of the
+	// oauth-server, the audit log records every login attempt
+	accessLogFormatJSON   = "json"
+	accessLogFormatLegacy = "legacy"
+
+	// accessLogDestinationFile writes the audit log to /var/log/oauth-server on the node
+	accessLogDestinationFile = "file"
+	// accessLogDestinationStdout writes the audit log to the output of the oauth-server
+	// container, interleaved with its logs, for the log collectors of the cluster to ship
+	accessLogDestinationStdout = "stdout"
+)
+
+// deploymentOverrides are the knobs of the oauth-server deployment that can be
+// set in the "oauthServerDeployment" key of the operator's unsupportedConfigOverrides
+type deploymentOverrides struct {
+	// PodAntiAffinity is either "soft" or "hard", defaults to "soft"
+	PodAntiAffinity string `json:"podAntiAffinity,omitempty"`
+	// AvoidKubeAPIServerNodes prefers to schedule the oauth-server pods away from the nodes
+	// that run a kube-apiserver so that its restarts during upgrades don't cut them off
+	AvoidKubeAPIServerNodes bool `json:"avoidKubeAPIServerNodes,omitempty"`
+	// ImagePullSecret is the name of a dockerconfigjson secret in the openshift-authentication
+	// namespace that is used to pull the oauth-server image
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+	// RolloutTrigger is either "resourceVersion" or "contentHash", defaults to "resourceVersion"
+	RolloutTrigger string `json:"rolloutTrigger,omitempty"`
+	// RolloutStrategy is either "surge" or "replace", defaults to "surge" unless the pods
+	// are strictly spread across nodes where a surge pod would never be scheduled
+	RolloutStrategy string `json:"rolloutStrategy,omitempty"`
+	// MaxSurge is the number or the percentage of the replicas brought up on top of the
+	// desired ones during a "surge" rollout, defaults to 1
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// ExtraEnv are additional environment variables of the oauth-server container,
+	// e.g. GODEBUG, the variables managed by the operator cannot be overridden
+	ExtraEnv []extraEnvVar `json:"extraEnv,omitempty"`
+	// StartupProbe tunes the startup probe of the oauth-server container, the
+	// defaults of the deployment asset are kept for the unset fields
+	StartupProbe startupProbeOverrides `json:"startupProbe,omitempty"`
+	// DNSPolicy is the DNS policy of the oauth-server pods, defaults to that of the deployment asset
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+	// DNSConfig adds nameservers, search domains and resolver options to the oauth-server
+	// pods, e.g. to resolve the hostnames of identity providers
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+	// AutomountServiceAccountToken brings back the automounted service account token of the
+	// oauth-server pods in place of the token projected into the oauth-server container
+	AutomountServiceAccountToken bool `json:"automountServiceAccountToken,omitempty"`
+	// ServiceAccountToken tunes the token projected into the oauth-server container
+	ServiceAccountToken serviceAccountTokenOverrides `json:"serviceAccountToken,omitempty"`
+	// ReadinessGates are additional pod conditions, e.g. set by a service mesh, that must
+	// be true for an oauth-server pod to be considered ready
+	ReadinessGates []corev1.PodReadinessGate `json:"readinessGates,omitempty"`
+	// AccessLog tunes the audit log of the logins, it follows the audit profile of
+	// apiservers.config.openshift.io/cluster by default
+	AccessLog accessLogOverrides `json:"accessLog,omitempty"`
+}
+
+type accessLogOverrides struct {
+	// Enabled turns the audit log on or off regardless of the audit profile
+	Enabled *bool `json:"enabled,omitempty"`
+	// Format is either "json" or "legacy", defaults to "json"
+	Format string `json:"format,omitempty"`
+	// Destination is either "file" or "stdout", defaults to "file"
+	Destination string `json:"destination,omitempty"`
+}
+
+type startupProbeOverrides struct {
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+	PeriodSeconds    int32 `json:"periodSeconds,omitempty"`
+}
+
+type serviceAccountTokenOverrides struct {
+	// Audience of the projected token, defaults to the audiences of the kube-apiserver. The
+	// oauth-server calls the kube-apiserver with this token so it must be one of the audiences
+	// the kube-apiserver accepts.
+	Audience string `json:"audience,omitempty"`
+	// ExpirationSeconds is the requested lifetime of the projected token, the kubelet
+	// refreshes it before it expires
+	ExpirationSeconds int64 `json:"expirationSeconds,omitempty"`
+}
+
+type extraEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// reservedEnvVars are set on the oauth-server container by the operator
+var reservedEnvVars = sets.NewString("HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY")
+
+const (
+	// the resolver limits enforced by the kube-apiserver for pod specs
+	maxDNSNameservers = 3
+	maxDNSSearchPaths = 32
+
+	// the token expiration limits enforced by the kube-apiserver for projected volumes
+	minServiceAccountTokenExpirationSeconds = 10 * 60
+	maxServiceAccountTokenExpirationSeconds = 1 << 32
+)
+
+func getDeploymentOverrides(spec *operatorv1.OperatorSpec) (*deploymentOverrides, error) {
+	unsupportedConfig := struct {
+		OAuthServerDeployment deploymentOverrides `json:"oauthServerDeployment"`
+	}{}
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode unsupportedConfigOverrides: %w", err)
+	}
+
+	overrides := &unsupportedConfig.OAuthServerDeployment
+	switch overrides.PodAntiAffinity {
+	case "":
+		overrides.PodAntiAffinity = podAntiAffinitySoft
+	case podAntiAffinitySoft, podAntiAffinityHard:
+	default:
+		return nil, fmt.Errorf("unsupported oauthServerDeployment.podAntiAffinity %q, must be either %q or %q",
+			overrides.PodAntiAffinity, podAntiAffinitySoft, podAntiAffinityHard)
+	}
+
+	switch overrides.RolloutTrigger {
+	case "":
+		overrides.RolloutTrigger = rolloutTriggerResourceVersion
+	case rolloutTriggerResourceVersion, rolloutTriggerContentHash:
+	default:
+		return nil, fmt.Errorf("unsupported oauthServerDeployment.rolloutTrigger %q, must be either %q or %q",
+			overrides.RolloutTrigger, rolloutTriggerResourceVersion, rolloutTriggerContentHash)
+	}
+
+	switch overrides.RolloutStrategy {
+	case "":
+		overrides.RolloutStrategy = rolloutStrategySurge
+		if overrides.PodAntiAffinity == podAntiAffinityHard {
+			overrides.RolloutStrategy = rolloutStrategyReplace
+		}
+	case rolloutStrategySurge:
+		if overrides.PodAntiAffinity == podAntiAffinityHard {
+			return nil, fmt.Errorf("oauthServerDeployment.rolloutStrategy %q cannot be used with the %q oauthServerDeployment.podAntiAffinity, the surge pods would never be scheduled",
+				rolloutStrategySurge, podAntiAffinityHard)
+		}
+	case rolloutStrategyReplace:
+	default:
+		return nil, fmt.Errorf("unsupported oauthServerDeployment.rolloutStrategy %q, must be either %q or %q",
+			overrides.RolloutStrategy, rolloutStrategySurge, rolloutStrategyReplace)
+	}
+
+	if overrides.MaxSurge != nil {
+		if err := validateMaxSurge(overrides.MaxSurge, overrides.RolloutStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	seenEnvVars := sets.NewString()
+	for _, env := range overrides.ExtraEnv {
+		if errs := validation.IsEnvVarName(env.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid oauthServerDeployment.extraEnv name %q: %s", env.Name, strings.Join(errs, ", "))
+		}
+		// the proxy variables are honored in lowercase as well
+		if reservedEnvVars.Has(strings.ToUpper(env.Name)) {
+			return nil, fmt.Errorf("oauthServerDeployment.extraEnv must not override the operator-managed environment variable %q", env.Name)
+		}
+		if seenEnvVars.Has(env.Name) {
+			return nil, fmt.Errorf("oauthServerDeployment.extraEnv sets the environment variable %q more than once", env.Name)
+		}
+		seenEnvVars.Insert(env.Name)
+	}
+
+	if overrides.StartupProbe.FailureThreshold < 0 || overrides.StartupProbe.PeriodSeconds < 0 {
+		return nil, fmt.Errorf("oauthServerDeployment.startupProbe failureThreshold and periodSeconds must be positive, got %d and %d",
+			overrides.StartupProbe.FailureThreshold, overrides.StartupProbe.PeriodSeconds)
+	}
+
+	if err := validateDNSOverrides(overrides.DNSPolicy, overrides.DNSConfig); err != nil {
+		return nil, err
+	}
+
+	if err := validateServiceAccountTokenOverrides(overrides.ServiceAccountToken, overrides.AutomountServiceAccountToken); err != nil {
+		return nil, err
+	}
+
+	seenReadinessGates := sets.NewString()
+	for _, gate := range overrides.ReadinessGates {
+		if errs := validation.IsQualifiedName(string(gate.ConditionType)); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid oauthServerDeployment.readinessGates condition type %q: %s", gate.ConditionType, strings.Join(errs, ", "))
+		}
+		if seenReadinessGates.Has(string(gate.ConditionType)) {
+			return nil, fmt.Errorf("oauthServerDeployment.readinessGates lists the condition type %q more than once", gate.ConditionType)
+		}
+		seenReadinessGates.Insert(string(gate.ConditionType))
+	}
+
+	if err := validateAccessLogOverrides(overrides.AccessLog); err != nil {
+		return nil, err
+	}
+
+	if len(overrides.ImagePullSecret) > 0 {
+		if errs := validation.IsDNS1123Subdomain(overrides.ImagePullSecret); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid oauthServerDeployment.imagePullSecret %q: %s", overrides.ImagePullSecret, strings.Join(errs, ", "))
+		}
+	}
+
+	return overrides, nil
+}
+
+// rolloutTriggers returns the overrides in a form that can be tracked among
+// the resource versions of the deployment
+func (o *deploymentOverrides) rolloutTriggers() []string {
+	triggers := []string{"podAntiAffinity:" + o.PodAntiAffinity, "rolloutStrategy:" + o.RolloutStrategy}
+	if o.MaxSurge != nil {
+		triggers = append(triggers, "maxSurge:"+o.MaxSurge.String())
+	}
+	if o.AvoidKubeAPIServerNodes {
+		triggers = append(triggers, "avoidKubeAPIServerNodes:true")
+	}
+	if o.AutomountServiceAccountToken {
+		triggers = append(triggers, "automountServiceAccountToken:true")
+	}
+	if len(o.ServiceAccountToken.Audience) > 0 {
+		triggers = append(triggers, "serviceAccountToken.audience:"+o.ServiceAccountToken.Audience)
+	}
+	if o.ServiceAccountToken.ExpirationSeconds > 0 {
+		triggers = append(triggers, fmt.Sprintf("serviceAccountToken.expirationSeconds:%d", o.ServiceAccountToken.ExpirationSeconds))
+	}
+	if len(o.ImagePullSecret) > 0 {
+		triggers = append(triggers, "imagePullSecret:"+o.ImagePullSecret)
+	}
+	for _, env := range o.ExtraEnv {
+		triggers = append(triggers, "extraEnv:"+env.Name+"="+env.Value)
+	}
+	if o.StartupProbe.FailureThreshold > 0 {
+		triggers = append(triggers, fmt.Sprintf("startupProbe.failureThreshold:%d", o.StartupProbe.FailureThreshold))
+	}
+	if o.StartupProbe.PeriodSeconds > 0 {
+		triggers = append(triggers, fmt.Sprintf("startupProbe.periodSeconds:%d", o.StartupProbe.PeriodSeconds))
+	}
+	if len(o.DNSPolicy) > 0 {
+		triggers = append(triggers, "dnsPolicy:"+string(o.DNSPolicy))
+	}
+	if o.DNSConfig != nil {
+		triggers = append(triggers, "dnsConfig.nameservers:"+strings.Join(o.DNSConfig.Nameservers, ","))
+		triggers = append(triggers, "dnsConfig.searches:"+strings.Join(o.DNSConfig.Searches, ","))
+		for _, option := range o.DNSConfig.Options {
+			trigger := "dnsConfig.option:" + option.Name
+			if option.Value != nil {
+				trigger += "=" + *option.Value
+			}
+			triggers = append(triggers, trigger)
+		}
+	}
+	for _, gate := range o.ReadinessGates {
+		triggers = append(triggers, "readinessGate:"+string(gate.ConditionType))
+	}
+	if o.AccessLog.Enabled != nil {
+		triggers = append(triggers, fmt.Sprintf("accessLog.enabled:%t", *o.AccessLog.Enabled))
+	}
+	if len(o.AccessLog.Format) > 0 {
+		triggers = append(triggers, "accessLog.format:"+o.AccessLog.Format)
+	}
+	if len(o.AccessLog.Destination) > 0 {
+		triggers = append(triggers, "accessLog.destination:"+o.AccessLog.Destination)
+	}
+	// switching the trigger changes all the tracked versions anyway
+	if o.RolloutTrigger != rolloutTriggerResourceVersion {
+		triggers = append(triggers, "rolloutTrigger:"+o.RolloutTrigger)
+	}
+	return triggers
+}
+
+// validateServiceAccountTokenOverrides rejects the token settings the kube-apiserver would refuse,
+// they apply to the projected token so they cannot be combined with the automounted one
+func validateServiceAccountTokenOverrides(token serviceAccountTokenOverrides, automount bool) error {
+	if token == (serviceAccountTokenOverrides{}) {
+		return nil
+	}
+	if automount {
+		return fmt.Errorf("oauthServerDeployment.serviceAccountToken cannot be used with oauthServerDeployment.automountServiceAccountToken, the automounted token has neither an audience nor an expiration")
+	}
+
+	if strings.ContainsAny(token.Audience, " \t\n,") {
+		return fmt.Errorf("invalid oauthServerDeployment.serviceAccountToken.audience %q, must not contain whitespace or commas", token.Audience)
+	}
+
+	if expiration := token.ExpirationSeconds; expiration != 0 && (expiration < minServiceAccountTokenExpirationSeconds || expiration > maxServiceAccountTokenExpirationSeconds) {
+		return fmt.Errorf("oauthServerDeployment.serviceAccountToken.expirationSeconds must be between %d and %d, got %d",
+			minServiceAccountTokenExpirationSeconds, maxServiceAccountTokenExpirationSeconds, expiration)
+	}
+	return nil
+}
+
+// validateAccessLogOverrides rejects the audit log formats and destinations the oauth-server
+// does not support, neither applies when the audit log is turned off
+func validateAccessLogOverrides(accessLog accessLogOverrides) error {
+	switch accessLog.Format {
+	case "", accessLogFormatJSON, accessLogFormatLegacy:
+	default:
+		return fmt.Errorf("unsupported oauthServerDeployment.accessLog.format %q, must be either %q or %q",
+			accessLog.Format, accessLogFormatJSON, accessLogFormatLegacy)
+	}
+
+	switch accessLog.Destination {
+	case "", accessLogDestinationFile, accessLogDestinationStdout:
+	default:
+		return fmt.Errorf("unsupported oauthServerDeployment.accessLog.destination %q, must be either %q or %q",
+			accessLog.Destination, accessLogDestinationFile, accessLogDestinationStdout)
+	}
+
+	if accessLog.Enabled != nil && !*accessLog.Enabled && (len(accessLog.Format) > 0 || len(accessLog.Destination) > 0) {
+		return fmt.Errorf("oauthServerDeployment.accessLog.format and destination cannot be set when oauthServerDeployment.accessLog.enabled is false")
+	}
+	return nil
+}
+
+// validateMaxSurge rejects the surge values the kube-apiserver would refuse, the surge
+// must not be zero as no replica is allowed to be unavailable during a "surge" rollout
+func validateMaxSurge(maxSurge *intstr.IntOrString, strategy string) error {
+	if strategy != rolloutStrategySurge {
+		return fmt.Errorf("oauthServerDeployment.maxSurge can only be set with the %q oauthServerDeployment.rolloutStrategy", rolloutStrategySurge)
+	}
+
+	switch maxSurge.Type {
+	case intstr.Int:
+		if maxSurge.IntVal < 1 {
+			return fmt.Errorf("oauthServerDeployment.maxSurge must be at least 1, got %d", maxSurge.IntVal)
+		}
+	case intstr.String:
+		percent, err := strconv.Atoi(strings.TrimSuffix(maxSurge.StrVal, "%"))
+		if err != nil || !strings.HasSuffix(maxSurge.StrVal, "%") {
+			return fmt.Errorf("invalid oauthServerDeployment.maxSurge %q, must be a number or a percentage", maxSurge.StrVal)
+		}
+		if percent < 1 || percent > 100 {
+			return fmt.Errorf("oauthServerDeployment.maxSurge must be between 1%% and 100%%, got %q", maxSurge.StrVal)
+		}
+	}
+	return nil
+}
+
+// validateDNSOverrides rejects the DNS settings the kube-apiserver would refuse for the
+// oauth-server pods so that they are reported before the deployment is applied
+func validateDNSOverrides(policy corev1.DNSPolicy, config *corev1.PodDNSConfig) error {
+	switch policy {
+	case "", corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault:
+	case corev1.DNSNone:
+		if config == nil || len(config.Nameservers) == 0 {
+			return fmt.Errorf("oauthServerDeployment.dnsConfig.nameservers must be set when oauthServerDeployment.dnsPolicy is %q", corev1.DNSNone)
+		}
+	default:
+		return fmt.Errorf("unsupported oauthServerDeployment.dnsPolicy %q, must be one of %q, %q, %q or %q",
+			policy, corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault, corev1.DNSNone)
+	}
+
+	if config == nil {
+		return nil
+	}
+
+	if len(config.Nameservers) > maxDNSNameservers {
+		return fmt.Errorf("oauthServerDeployment.dnsConfig must not have more than %d nameservers, got %d", maxDNSNameservers, len(config.Nameservers))
+	}
+	for _, nameserver := range config.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return fmt.Errorf("invalid oauthServerDeployment.dnsConfig nameserver %q, must be an IP address", nameserver)
+		}
+	}
+
+	if len(config.Searches) > maxDNSSearchPaths {
+		return fmt.Errorf("oauthServerDeployment.dnsConfig must not have more than %d search domains, got %d", maxDNSSearchPaths, len(config.Searches))
+	}
+	for _, search := range config.Searches {
+		// a trailing dot makes the domain fully qualified
+		if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, ".")); len(errs) > 0 {
+			return fmt.Errorf("invalid oauthServerDeployment.dnsConfig search domain %q: %s", search, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, option := range config.Options {
+		if len(option.Name) == 0 {
+			return fmt.Errorf("oauthServerDeployment.dnsConfig options must have a name")
+		}
+	}
+
+	return nil
+}
+
+// validateImagePullSecret checks that the image pull secret from the overrides exists
+// and that it can be used to pull images
+func validateImagePullSecret(secretLister corev1listers.SecretLister, name string) error {
+	secret, err := secretLister.Secrets(common.TargetNamespace).Get(name)
+	if err != nil {
+		return fmt.Errorf("unable to get the image pull secret %s/%s: %w", common.TargetNamespace, name, err)
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return fmt.Errorf("the image pull secret %s/%s must be of type %q, got %q", common.TargetNamespace, name, corev1.SecretTypeDockerConfigJson, secret.Type)
+	}
+	return nil
+}
+
+// setPodAntiAffinity spreads the oauth-server pods across nodes, preferably
+// or strictly based on the mode, and preferably across zones. The soft mode
+// keeps the preferred hostname term of the manifest as it is.
+func setPodAntiAffinity(spec *appsv1.DeploymentSpec, mode string, ensureAtMostOnePodPerNode ensureAtMostOnePodPerNodeFunc) error {
+	if mode == podAntiAffinityHard {
+		if err := ensureAtMostOnePodPerNode(spec, "oauth-openshift"); err != nil {
+			return fmt.Errorf("unable to ensure at most one pod per node: %v", err)
+		}
+	}
+
+	if spec.Template.Spec.Affinity == nil {
+		spec.Template.Spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Template.Spec.Affinity.PodAntiAffinity == nil {
+		spec.Template.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+
+	antiAffinity := spec.Template.Spec.Affinity.PodAntiAffinity
+	antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.WeightedPodAffinityTerm{
+			Weight: 50,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: spec.Selector.MatchLabels,
+				},
+				TopologyKey: corev1.LabelTopologyZone,
+			},
+		},
+	)
+
+	return nil
+}
+
+// setKubeAPIServerAntiAffinity prefers the nodes without a kube-apiserver pod for the
+// oauth-server pods, it's only a preference as all the control plane nodes usually run one
+func setKubeAPIServerAntiAffinity(spec *appsv1.DeploymentSpec) {
+	if spec.Template.Spec.Affinity == nil {
+		spec.Template.Spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Template.Spec.Affinity.PodAntiAffinity == nil {
+		spec.Template.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+
+	antiAffinity := spec.Template.Spec.Affinity.PodAntiAffinity
+	antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.WeightedPodAffinityTerm{
+			Weight: 25,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "openshift-kube-apiserver", "apiserver": "true"},
+				},
+				Namespaces:  []string{"openshift-kube-apiserver"},
+				TopologyKey: corev1.LabelHostname,
+			},
+		},
+	)
+}
+
+// setRolloutStrategy sets the rolling update parameters of the deployment so that
+// either no replica or at most one replica is missing during a rollout, the surge
+// overrides the single extra replica of a "surge" rollout
+func setRolloutStrategy(spec *appsv1.DeploymentSpec, strategy string, surge *intstr.IntOrString) {
+	maxUnavailable, maxSurge := intstr.FromInt(0), intstr.FromInt(1)
+	if strategy == rolloutStrategyReplace {
+		maxUnavailable, maxSurge = intstr.FromInt(1), intstr.FromInt(0)
+	} else if surge != nil {
+		maxSurge = *surge
+	}
+
+	spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
+		},
+	}
+}
+
+// envVars returns the extra environment variables in the form of the container spec
+func (o *deploymentOverrides) envVars() []corev1.EnvVar {
+	envVars := make([]corev1.EnvVar, 0, len(o.ExtraEnv))
+	for _, env := range o.ExtraEnv {
+		envVars = append(envVars, corev1.EnvVar{Name: env.Name, Value: env.Value})
+	}
+	return envVars
+}
+
+// setStartupProbe applies the startup probe overrides to the oauth-server container
+func (o *deploymentOverrides) setStartupProbe(container *corev1.Container) {
+	if container.StartupProbe == nil {
+		return
+	}
+	if o.StartupProbe.FailureThreshold > 0 {
+		container.StartupProbe.FailureThreshold = o.StartupProbe.FailureThreshold
+	}
+	if o.StartupProbe.PeriodSeconds > 0 {
+		container.StartupProbe.PeriodSeconds = o.StartupProbe.PeriodSeconds
+	}
+}
+
+// setDNS applies the DNS overrides to the oauth-server pods
+func (o *deploymentOverrides) setDNS(podSpec *corev1.PodSpec) {
+	if len(o.DNSPolicy) > 0 {
+		podSpec.DNSPolicy = o.DNSPolicy
+	}
+	if o.DNSConfig != nil {
+		podSpec.DNSConfig = o.DNSConfig.DeepCopy()
+	}
+}
+
+// kubeAPIAccessVolume is the projected service account token of the oauth-server container
+const kubeAPIAccessVolume = "kube-api-access"
+
+// setServiceAccountToken replaces the projected service account token with the automounted one
+// when requested by the overrides, or applies the audience and expiration to the projected one
+func (o *deploymentOverrides) setServiceAccountToken(podSpec *corev1.PodSpec) {
+	if !o.AutomountServiceAccountToken {
+		o.setProjectedServiceAccountToken(podSpec)
+		return
+	}
+
+	automount := true
+	podSpec.AutomountServiceAccountToken = &automount
+
+	volumes := []corev1.Volume{}
+	for _, volume := range podSpec.Volumes {
+		if volume.Name != kubeAPIAccessVolume {
+			volumes = append(volumes, volume)
+		}
+	}
+	podSpec.Volumes = volumes
+
+	for i := range podSpec.Containers {
+		mounts := []corev1.VolumeMount{}
+		for _, mount := range podSpec.Containers[i].VolumeMounts {
+			if mount.Name != kubeAPIAccessVolume {
+				mounts = append(mounts, mount)
+			}
+		}
+		podSpec.Containers[i].VolumeMounts = mounts
+	}
+}
+
+func (o *deploymentOverrides) setProjectedServiceAccountToken(podSpec *corev1.PodSpec) {
+	for _, volume := range podSpec.Volumes {
+		if volume.Name != kubeAPIAccessVolume || volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.ServiceAccountToken == nil {
+				continue
+			}
+			if len(o.ServiceAccountToken.Audience) > 0 {
+				source.ServiceAccountToken.Audience = o.ServiceAccountToken.Audience
+			}
+			if o.ServiceAccountToken.ExpirationSeconds > 0 {
+				expiration := o.ServiceAccountToken.ExpirationSeconds
+				source.ServiceAccountToken.ExpirationSeconds = &expiration
+			}
+		}
+	}
+}
+
+// setReadinessGates adds the readiness gates from the overrides to the oauth-server pods
+func (o *deploymentOverrides) setReadinessGates(podSpec *corev1.PodSpec) {
+	podSpec.ReadinessGates = append(podSpec.ReadinessGates, o.ReadinessGates...)
+}
+
+// setAccessLogArguments applies the audit log overrides to the arguments of the oauth-server,
+// the arguments observed from the audit profile are kept for anything that is not overridden
+func (o *deploymentOverrides) setAccessLogArguments(args arguments.ServerArguments) error {
+	if enabled := o.AccessLog.Enabled; enabled != nil {
+		if !*enabled {
+			for name := range args {
+				if strings.HasPrefix(name, "audit-") {
+					delete(args, name)
+				}
+			}
+			return nil
+		}
+
+		if _, ok := args["audit-log-path"]; !ok {
+			auditArgs, err := arguments.Parse(observeoauth.AuditArguments())
+			if err != nil {
+				return fmt.Errorf("unable to parse the audit arguments: %w", err)
+			}
+			for name, values := range auditArgs {
+				args[name] = values
+			}
+		}
+	}
+
+	if _, ok := args["audit-log-path"]; !ok {
+		// the audit profile turned the audit log off
+		return nil
+	}
+
+	if len(o.AccessLog.Format) > 0 {
+		args["audit-log-format"] = []string{o.AccessLog.Format}
+	}
+	if o.AccessLog.Destination == accessLogDestinationStdout {
+		args["audit-log-path"] = []string{"-"}
+		// there are no files to rotate
+		delete(args, "audit-log-maxsize")
+		delete(args, "audit-log-maxbackup")
+	}
+	return nil
+}
@@ -0,0 +1,627 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common/arguments"
+	observeoauth "github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation/oauth"
+	"github.com/openshift/cluster-authentication-operator/pkg/operator/assets"
+)
+
+func TestGetDeploymentOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     string
+		want          *deploymentOverrides
+		expectedError bool
+	}{
+		{
+			name: "no overrides",
+			want: &deploymentOverrides{PodAntiAffinity: podAntiAffinitySoft, RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategySurge},
+		},
+		{
+			name:      "unrelated overrides",
+			overrides: `{"oauthServer": {"servingInfo": {"minTLSVersion": "VersionTLS12"}}}`,
+			want:      &deploymentOverrides{PodAntiAffinity: podAntiAffinitySoft, RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategySurge},
+		},
+		{
+			name:      "hard anti-affinity",
+			overrides: `{"oauthServerDeployment": {"podAntiAffinity": "hard"}}`,
+			want:      &deploymentOverrides{PodAntiAffinity: podAntiAffinityHard, RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategyReplace},
+		},
+		{
+			name:      "hard anti-affinity in yaml",
+			overrides: "oauthServerDeployment:\n  podAntiAffinity: hard\n",
+			want:      &deploymentOverrides{PodAntiAffinity: podAntiAffinityHard, RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategyReplace},
+		},
+		{
+			name:      "image pull secret",
+			overrides: `{"oauthServerDeployment": {"imagePullSecret": "my-registry"}}`,
+			want:      &deploymentOverrides{PodAntiAffinity: podAntiAffinitySoft, ImagePullSecret: "my-registry", RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategySurge},
+		},
+		{
+			name:          "invalid image pull secret name",
+			overrides:     `{"oauthServerDeployment": {"imagePullSecret": "My Registry"}}`,
+			expectedError: true,
+		},
+		{
+			name:      "content hash rollout trigger",
+			overrides: `{"oauthServerDeployment": {"rolloutTrigger": "contentHash"}}`,
+			want:      &deploymentOverrides{PodAntiAffinity: podAntiAffinitySoft, RolloutTrigger: rolloutTriggerContentHash, RolloutStrategy: rolloutStrategySurge},
+		},
+		{
+			name:          "unknown rollout trigger",
+			overrides:     `{"oauthServerDeployment": {"rolloutTrigger": "always"}}`,
+			expectedError: true,
+		},
+		{
+			name:      "extra env vars",
+			overrides: `{"oauthServerDeployment": {"extraEnv": [{"name": "GODEBUG", "value": "x509ignoreCN=0"}]}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity: podAntiAffinitySoft,
+				RolloutTrigger:  rolloutTriggerResourceVersion,
+				RolloutStrategy: rolloutStrategySurge,
+				ExtraEnv:        []extraEnvVar{{Name: "GODEBUG", Value: "x509ignoreCN=0"}},
+			},
+		},
+		{
+			name:          "extra env overrides a proxy var",
+			overrides:     `{"oauthServerDeployment": {"extraEnv": [{"name": "https_proxy", "value": "http://proxy:3128"}]}}`,
+			expectedError: true,
+		},
+		{
+			name:          "extra env set twice",
+			overrides:     `{"oauthServerDeployment": {"extraEnv": [{"name": "GODEBUG", "value": "a=1"}, {"name": "GODEBUG", "value": "b=1"}]}}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid extra env name",
+			overrides:     `{"oauthServerDeployment": {"extraEnv": [{"name": "1GODEBUG", "value": "a=1"}]}}`,
+			expectedError: true,
+		},
+		{
+			name:      "startup probe",
+			overrides: `{"oauthServerDeployment": {"startupProbe": {"failureThreshold": 60}}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity: podAntiAffinitySoft,
+				RolloutTrigger:  rolloutTriggerResourceVersion,
+				RolloutStrategy: rolloutStrategySurge,
+				StartupProbe:    startupProbeOverrides{FailureThreshold: 60},
+			},
+		},
+		{
+			name:          "negative startup probe period",
+			overrides:     `{"oauthServerDeployment": {"startupProbe": {"periodSeconds": -1}}}`,
+			expectedError: true,
+		},
+		{
+			name:      "dns config",
+			overrides: `{"oauthServerDeployment": {"dnsPolicy": "None", "dnsConfig": {"nameservers": ["10.0.0.10"], "searches": ["corp.example.com."]}}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity: podAntiAffinitySoft,
+				RolloutTrigger:  rolloutTriggerResourceVersion,
+				RolloutStrategy: rolloutStrategySurge,
+				DNSPolicy:       corev1.DNSNone,
+				DNSConfig:       &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.10"}, Searches: []string{"corp.example.com."}},
+			},
+		},
+		{
+			name:          "dns policy None without nameservers",
+			overrides:     `{"oauthServerDeployment": {"dnsPolicy": "None", "dnsConfig": {"searches": ["corp.example.com"]}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown dns policy",
+			overrides:     `{"oauthServerDeployment": {"dnsPolicy": "Custom"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "dns nameserver is not an IP",
+			overrides:     `{"oauthServerDeployment": {"dnsConfig": {"nameservers": ["dns.example.com"]}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "too many dns nameservers",
+			overrides:     `{"oauthServerDeployment": {"dnsConfig": {"nameservers": ["10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"]}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid dns search domain",
+			overrides:     `{"oauthServerDeployment": {"dnsConfig": {"searches": ["corp_example.com"]}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unnamed dns option",
+			overrides:     `{"oauthServerDeployment": {"dnsConfig": {"options": [{"value": "2"}]}}}`,
+			expectedError: true,
+		},
+		{
+			name:      "avoid kube-apiserver nodes",
+			overrides: `{"oauthServerDeployment": {"avoidKubeAPIServerNodes": true}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity:         podAntiAffinitySoft,
+				RolloutTrigger:          rolloutTriggerResourceVersion,
+				RolloutStrategy:         rolloutStrategySurge,
+				AvoidKubeAPIServerNodes: true,
+			},
+		},
+		{
+			name:      "automounted service account token",
+			overrides: `{"oauthServerDeployment": {"automountServiceAccountToken": true}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity:              podAntiAffinitySoft,
+				RolloutTrigger:               rolloutTriggerResourceVersion,
+				RolloutStrategy:              rolloutStrategySurge,
+				AutomountServiceAccountToken: true,
+			},
+		},
+		{
+			name:      "projected service account token",
+			overrides: `{"oauthServerDeployment": {"serviceAccountToken": {"audience": "https://kubernetes.default.svc", "expirationSeconds": 7200}}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity:     podAntiAffinitySoft,
+				RolloutTrigger:      rolloutTriggerResourceVersion,
+				RolloutStrategy:     rolloutStrategySurge,
+				ServiceAccountToken: serviceAccountTokenOverrides{Audience: "https://kubernetes.default.svc", ExpirationSeconds: 7200},
+			},
+		},
+		{
+			name:          "invalid service account token audience",
+			overrides:     `{"oauthServerDeployment": {"serviceAccountToken": {"audience": "api, vault"}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "too short service account token expiration",
+			overrides:     `{"oauthServerDeployment": {"serviceAccountToken": {"expirationSeconds": 60}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "service account token settings with the automounted token",
+			overrides:     `{"oauthServerDeployment": {"automountServiceAccountToken": true, "serviceAccountToken": {"audience": "api"}}}`,
+			expectedError: true,
+		},
+		{
+			name:      "readiness gates",
+			overrides: `{"oauthServerDeployment": {"readinessGates": [{"conditionType": "mesh.example.com/ready"}]}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity: podAntiAffinitySoft,
+				RolloutTrigger:  rolloutTriggerResourceVersion,
+				RolloutStrategy: rolloutStrategySurge,
+				ReadinessGates:  []corev1.PodReadinessGate{{ConditionType: "mesh.example.com/ready"}},
+			},
+		},
+		{
+			name:          "invalid readiness gate",
+			overrides:     `{"oauthServerDeployment": {"readinessGates": [{"conditionType": "mesh ready"}]}}`,
+			expectedError: true,
+		},
+		{
+			name:          "duplicate readiness gate",
+			overrides:     `{"oauthServerDeployment": {"readinessGates": [{"conditionType": "MeshReady"}, {"conditionType": "MeshReady"}]}}`,
+			expectedError: true,
+		},
+		{
+			name:      "replace rollout strategy",
+			overrides: `{"oauthServerDeployment": {"rolloutStrategy": "replace"}}`,
+			want:      &deploymentOverrides{PodAntiAffinity: podAntiAffinitySoft, RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategyReplace},
+		},
+		{
+			name:      "max surge",
+			overrides: `{"oauthServerDeployment": {"maxSurge": "50%"}}`,
+			want: &deploymentOverrides{
+				PodAntiAffinity: podAntiAffinitySoft,
+				RolloutTrigger:  rolloutTriggerResourceVersion,
+				RolloutStrategy: rolloutStrategySurge,
+				MaxSurge:        intstrPtr(intstr.FromString("50%")),
+			},
+		},
+		{
+			name:          "zero max surge",
+			overrides:     `{"oauthServerDeployment": {"maxSurge": 0}}`,
+			expectedError: true,
+		},
+		{
+			name:          "max surge above 100%",
+			overrides:     `{"oauthServerDeployment": {"maxSurge": "150%"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "max surge that is not a percentage",
+			overrides:     `{"oauthServerDeployment": {"maxSurge": "two"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "max surge with the replace rollout strategy",
+			overrides:     `{"oauthServerDeployment": {"rolloutStrategy": "replace", "maxSurge": 2}}`,
+			expectedError: true,
+		},
+		{
+			name:          "max surge with hard anti-affinity",
+			overrides:     `{"oauthServerDeployment": {"podAntiAffinity": "hard", "maxSurge": 2}}`,
+			expectedError: true,
+		},
+		{
+			name:          "surge rollout strategy with hard anti-affinity",
+			overrides:     `{"oauthServerDeployment": {"podAntiAffinity": "hard", "rolloutStrategy": "surge"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown rollout strategy",
+			overrides:     `{"oauthServerDeployment": {"rolloutStrategy": "recreate"}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown anti-affinity",
+			overrides:     `{"oauthServerDeployment": {"podAntiAffinity": "sometimes"}}`,
+			expectedError: true,
+		},
+		{
+			name:      "access log to stdout",
+			overrides: `{"oauthServerDeployment": {"accessLog": {"format": "legacy", "destination": "stdout"}}}`,
+			want: &deploymentOverrides{PodAntiAffinity: podAntiAffinitySoft, RolloutTrigger: rolloutTriggerResourceVersion, RolloutStrategy: rolloutStrategySurge,
+				AccessLog: accessLogOverrides{Format: accessLogFormatLegacy, Destination: accessLogDestinationStdout}},
+		},
+		{
+			name:          "unknown access log format",
+			overrides:     `{"oauthServerDeployment": {"accessLog": {"format": "text"}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown access log destination",
+			overrides:     `{"oauthServerDeployment": {"accessLog": {"destination": "syslog"}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "access log format when disabled",
+			overrides:     `{"oauthServerDeployment": {"accessLog": {"enabled": false, "format": "json"}}}`,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &operatorv1.OperatorSpec{}
+			if len(tt.overrides) > 0 {
+				spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tt.overrides)}
+			}
+
+			got, err := getDeploymentOverrides(spec)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetPodAntiAffinity(t *testing.T) {
+	tests := []struct {
+		name              string
+		mode              string
+		expectedRequired  int
+		expectedPreferred []string
+	}{
+		{
+			name:              "soft",
+			mode:              podAntiAffinitySoft,
+			expectedPreferred: []string{corev1.LabelHostname, corev1.LabelTopologyZone},
+		},
+		{
+			name:              "hard",
+			mode:              podAntiAffinityHard,
+			expectedRequired:  1,
+			expectedPreferred: []string{corev1.LabelTopologyZone},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+			require.NoError(t, setPodAntiAffinity(&deployment.Spec, tt.mode, workload.EnsureAtMostOnePodPerNode))
+
+			antiAffinity := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity
+			require.Len(t, antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, tt.expectedRequired)
+
+			preferredKeys := []string{}
+			for _, term := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+				preferredKeys = append(preferredKeys, term.PodAffinityTerm.TopologyKey)
+			}
+			require.Equal(t, tt.expectedPreferred, preferredKeys)
+		})
+	}
+}
+
+func TestSetKubeAPIServerAntiAffinity(t *testing.T) {
+	deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+	require.NoError(t, setPodAntiAffinity(&deployment.Spec, podAntiAffinitySoft, workload.EnsureAtMostOnePodPerNode))
+	setKubeAPIServerAntiAffinity(&deployment.Spec)
+
+	preferred := deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	require.Len(t, preferred, 3)
+	// the oauth-server spreading is kept and outweighs the kube-apiserver term
+	kasTerm := preferred[2]
+	require.Less(t, kasTerm.Weight, preferred[1].Weight)
+	require.Equal(t, corev1.LabelHostname, kasTerm.PodAffinityTerm.TopologyKey)
+	require.Equal(t, []string{"openshift-kube-apiserver"}, kasTerm.PodAffinityTerm.Namespaces)
+	require.Equal(t, "openshift-kube-apiserver", kasTerm.PodAffinityTerm.LabelSelector.MatchLabels["app"])
+}
+
+func TestValidateImagePullSecret(t *testing.T) {
+	tests := []struct {
+		name          string
+		secret        *corev1.Secret
+		expectedError bool
+	}{
+		{
+			name:          "missing secret",
+			expectedError: true,
+		},
+		{
+			name: "dockerconfigjson secret",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "pull-secret"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+			},
+		},
+		{
+			name: "opaque secret",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "pull-secret"},
+				Type:       corev1.SecretTypeOpaque,
+			},
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tt.secret != nil {
+				require.NoError(t, indexer.Add(tt.secret))
+			}
+
+			err := validateImagePullSecret(corev1listers.NewSecretLister(indexer), "pull-secret")
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSetStartupProbe(t *testing.T) {
+	tests := []struct {
+		name                     string
+		overrides                startupProbeOverrides
+		expectedFailureThreshold int32
+		expectedPeriodSeconds    int32
+	}{
+		{
+			name:                     "defaults",
+			expectedFailureThreshold: 30,
+			expectedPeriodSeconds:    10,
+		},
+		{
+			name:                     "failure threshold",
+			overrides:                startupProbeOverrides{FailureThreshold: 60},
+			expectedFailureThreshold: 60,
+			expectedPeriodSeconds:    10,
+		},
+		{
+			name:                     "both",
+			overrides:                startupProbeOverrides{FailureThreshold: 12, PeriodSeconds: 20},
+			expectedFailureThreshold: 12,
+			expectedPeriodSeconds:    20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+			container := &deployment.Spec.Template.Spec.Containers[0]
+
+			(&deploymentOverrides{StartupProbe: tt.overrides}).setStartupProbe(container)
+
+			require.NotNil(t, container.StartupProbe)
+			require.Equal(t, tt.expectedFailureThreshold, container.StartupProbe.FailureThreshold)
+			require.Equal(t, tt.expectedPeriodSeconds, container.StartupProbe.PeriodSeconds)
+		})
+	}
+}
+
+func TestSetAccessLogArguments(t *testing.T) {
+	enabled, disabled := true, false
+	auditArgs := func() arguments.ServerArguments {
+		args, err := arguments.Parse(observeoauth.AuditArguments())
+		require.NoError(t, err)
+		args["v"] = []string{"2"}
+		return args
+	}
+	withoutAudit := arguments.ServerArguments{"v": {"2"}}
+
+	tests := []struct {
+		name      string
+		accessLog accessLogOverrides
+		args      arguments.ServerArguments
+		expected  func(args arguments.ServerArguments)
+	}{
+		{
+			name: "defaults",
+			args: auditArgs(),
+			expected: func(args arguments.ServerArguments) {
+				require.Equal(t, auditArgs(), args)
+			},
+		},
+		{
+			name: "audit profile None",
+			args: arguments.ServerArguments{"v": {"2"}},
+			expected: func(args arguments.ServerArguments) {
+				require.Equal(t, withoutAudit, args)
+			},
+		},
+		{
+			name:      "disabled",
+			accessLog: accessLogOverrides{Enabled: &disabled},
+			args:      auditArgs(),
+			expected: func(args arguments.ServerArguments) {
+				require.Equal(t, withoutAudit, args)
+			},
+		},
+		{
+			name:      "enabled with the audit profile None",
+			accessLog: accessLogOverrides{Enabled: &enabled},
+			args:      arguments.ServerArguments{"v": {"2"}},
+			expected: func(args arguments.ServerArguments) {
+				require.Equal(t, auditArgs(), args)
+			},
+		},
+		{
+			name:      "legacy format to stdout",
+			accessLog: accessLogOverrides{Format: accessLogFormatLegacy, Destination: accessLogDestinationStdout},
+			args:      auditArgs(),
+			expected: func(args arguments.ServerArguments) {
+				require.Equal(t, []string{"-"}, args["audit-log-path"])
+				require.Equal(t, []string{"legacy"}, args["audit-log-format"])
+				require.NotContains(t, args, "audit-log-maxsize")
+				require.NotContains(t, args, "audit-log-maxbackup")
+				require.Equal(t, []string{"/var/run/configmaps/audit/audit.yaml"}, args["audit-policy-file"])
+			},
+		},
+		{
+			name:      "stdout with the audit profile None",
+			accessLog: accessLogOverrides{Destination: accessLogDestinationStdout},
+			args:      arguments.ServerArguments{"v": {"2"}},
+			expected: func(args arguments.ServerArguments) {
+				require.Equal(t, withoutAudit, args)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, (&deploymentOverrides{AccessLog: tt.accessLog}).setAccessLogArguments(tt.args))
+			tt.expected(tt.args)
+		})
+	}
+}
+
+func TestSetServiceAccountToken(t *testing.T) {
+	hasTokenVolume := func(podSpec *corev1.PodSpec) (bool, bool) {
+		volume, mount := false, false
+		for _, v := range podSpec.Volumes {
+			volume = volume || v.Name == kubeAPIAccessVolume
+		}
+		for _, m := range podSpec.Containers[0].VolumeMounts {
+			mount = mount || (m.Name == kubeAPIAccessVolume && m.MountPath == "/var/run/secrets/kubernetes.io/serviceaccount")
+		}
+		return volume, mount
+	}
+
+	tests := []struct {
+		name               string
+		automount          bool
+		token              serviceAccountTokenOverrides
+		expectedAutomount  bool
+		expectedProjected  bool
+		expectedAudience   string
+		expectedExpiration int64
+	}{
+		{
+			name:               "projected token",
+			expectedProjected:  true,
+			expectedExpiration: 3607,
+		},
+		{
+			name:               "projected token with audience and expiration",
+			token:              serviceAccountTokenOverrides{Audience: "https://kubernetes.default.svc", ExpirationSeconds: 7200},
+			expectedProjected:  true,
+			expectedAudience:   "https://kubernetes.default.svc",
+			expectedExpiration: 7200,
+		},
+		{
+			name:              "automounted token",
+			automount:         true,
+			expectedAutomount: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+			podSpec := &deployment.Spec.Template.Spec
+
+			(&deploymentOverrides{AutomountServiceAccountToken: tt.automount, ServiceAccountToken: tt.token}).setServiceAccountToken(podSpec)
+
+			require.NotNil(t, podSpec.AutomountServiceAccountToken)
+			require.Equal(t, tt.expectedAutomount, *podSpec.AutomountServiceAccountToken)
+			volume, mount := hasTokenVolume(podSpec)
+			require.Equal(t, tt.expectedProjected, volume)
+			require.Equal(t, tt.expectedProjected, mount)
+
+			for _, v := range podSpec.Volumes {
+				if v.Name != kubeAPIAccessVolume {
+					continue
+				}
+				token := v.Projected.Sources[0].ServiceAccountToken
+				require.NotNil(t, token)
+				require.Equal(t, tt.expectedAudience, token.Audience)
+				require.Equal(t, tt.expectedExpiration, *token.ExpirationSeconds)
+			}
+		})
+	}
+}
+
+func TestSetRolloutStrategy(t *testing.T) {
+	tests := []struct {
+		name                   string
+		strategy               string
+		maxSurge               *intstr.IntOrString
+		expectedMaxUnavailable int
+		expectedMaxSurge       string
+	}{
+		{
+			name:                   "surge",
+			strategy:               rolloutStrategySurge,
+			expectedMaxUnavailable: 0,
+			expectedMaxSurge:       "1",
+		},
+		{
+			name:                   "surge with max surge",
+			strategy:               rolloutStrategySurge,
+			maxSurge:               intstrPtr(intstr.FromString("50%")),
+			expectedMaxUnavailable: 0,
+			expectedMaxSurge:       "50%",
+		},
+		{
+			name:                   "replace",
+			strategy:               rolloutStrategyReplace,
+			expectedMaxUnavailable: 1,
+			expectedMaxSurge:       "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("oauth-openshift/deployment.yaml"))
+			setRolloutStrategy(&deployment.Spec, tt.strategy, tt.maxSurge)
+
+			require.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+			require.Equal(t, tt.expectedMaxUnavailable, deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+			require.Equal(t, tt.expectedMaxSurge, deployment.Spec.Strategy.RollingUpdate.MaxSurge.String())
+		})
+	}
+}
+
+func intstrPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
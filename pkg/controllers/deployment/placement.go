@@ -0,0 +1,114 @@
+package deployment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+const (
+	masterNodeRoleLabel       = "node-role.kubernetes.io/master"
+	controlPlaneNodeRoleLabel = "node-role.kubernetes.io/control-plane"
+)
+
+// zonedPlatforms are the platforms whose control plane nodes are expected to carry
+// the zone label of the failure domain they run in
+var zonedPlatforms = map[configv1.PlatformType]bool{
+	configv1.AWSPlatformType:          true,
+	configv1.AzurePlatformType:        true,
+	configv1.GCPPlatformType:          true,
+	configv1.OpenStackPlatformType:    true,
+	configv1.IBMCloudPlatformType:     true,
+	configv1.AlibabaCloudPlatformType: true,
+	configv1.PowerVSPlatformType:      true,
+}
+
+// getControlPlaneNodeSelector returns the node selector that places the oauth-server
+// pods on the control plane. The master role is preferred as long as any node carries
+// it, clusters that have moved to the control-plane role get that one instead.
+func getControlPlaneNodeSelector(nodeLister corev1listers.NodeLister) (map[string]string, error) {
+	for _, roleLabel := range []string{masterNodeRoleLabel, controlPlaneNodeRoleLabel} {
+		selector, err := labels.Parse(roleLabel)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := nodeLister.List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the %s nodes: %w", roleLabel, err)
+		}
+		if len(nodes) > 0 {
+			return map[string]string{roleLabel: ""}, nil
+		}
+	}
+
+	// no control plane nodes found, keep the default placement
+	return map[string]string{masterNodeRoleLabel: ""}, nil
+}
+
+// nodeSelectorRolloutTrigger returns the node selector in a form that can be tracked
+// among the resource versions of the deployment
+func nodeSelectorRolloutTrigger(nodeSelector map[string]string) string {
+	pairs := make([]string, 0, len(nodeSelector))
+	for k, v := range nodeSelector {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return "nodeSelector:" + strings.Join(pairs, ",")
+}
+
+// getTopologySpreadConstraints returns the constraints that spread the oauth-server pods
+// across the zones of the control plane. Single replica control planes and platforms
+// without zones, like bare metal, get none.
+func getTopologySpreadConstraints(infraLister configv1listers.InfrastructureLister) ([]corev1.TopologySpreadConstraint, error) {
+	infra, err := infraLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the cluster infrastructure config: %w", err)
+	}
+
+	if infra.Status.ControlPlaneTopology == configv1.SingleReplicaTopologyMode {
+		return nil, nil
+	}
+
+	platform := infra.Status.Platform
+	if infra.Status.PlatformStatus != nil && len(infra.Status.PlatformStatus.Type) > 0 {
+		platform = infra.Status.PlatformStatus.Type
+	}
+	if !zonedPlatforms[platform] {
+		return nil, nil
+	}
+
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:     1,
+			TopologyKey: corev1.LabelTopologyZone,
+			// nodes of some installations might lack the zone label, never keep the pods pending
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "oauth-openshift"},
+			},
+		},
+	}, nil
+}
+
+// topologySpreadRolloutTrigger returns the topology spread constraints in a form that can
+// be tracked among the resource versions of the deployment
+func topologySpreadRolloutTrigger(constraints []corev1.TopologySpreadConstraint) string {
+	keys := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		keys = append(keys, fmt.Sprintf("%s/%d/%s", c.TopologyKey, c.MaxSkew, c.WhenUnsatisfiable))
+	}
+	sort.Strings(keys)
+	return "topologySpread:" + strings.Join(keys, ",")
+}
@@ -0,0 +1,127 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+func TestGetControlPlaneNodeSelector(t *testing.T) {
+	node := func(name string, roles ...string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		for _, role := range roles {
+			n.Labels[role] = ""
+		}
+		return n
+	}
+
+	tests := []struct {
+		name  string
+		nodes []*corev1.Node
+		want  map[string]string
+	}{
+		{
+			name: "no nodes",
+			want: map[string]string{masterNodeRoleLabel: ""},
+		},
+		{
+			name:  "master nodes",
+			nodes: []*corev1.Node{node("m0", masterNodeRoleLabel), node("w0", "node-role.kubernetes.io/worker")},
+			want:  map[string]string{masterNodeRoleLabel: ""},
+		},
+		{
+			name:  "nodes with both roles",
+			nodes: []*corev1.Node{node("m0", masterNodeRoleLabel, controlPlaneNodeRoleLabel)},
+			want:  map[string]string{masterNodeRoleLabel: ""},
+		},
+		{
+			name:  "control-plane role only",
+			nodes: []*corev1.Node{node("cp0", controlPlaneNodeRoleLabel), node("w0", "node-role.kubernetes.io/worker")},
+			want:  map[string]string{controlPlaneNodeRoleLabel: ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, n := range tt.nodes {
+				require.NoError(t, indexer.Add(n))
+			}
+
+			got, err := getControlPlaneNodeSelector(corev1listers.NewNodeLister(indexer))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTopologySpreadConstraints(t *testing.T) {
+	infra := func(platform configv1.PlatformType, topology configv1.TopologyMode) *configv1.Infrastructure {
+		return &configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.InfrastructureStatus{
+				ControlPlaneTopology: topology,
+				PlatformStatus:       &configv1.PlatformStatus{Type: platform},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		infra      *configv1.Infrastructure
+		wantSpread bool
+	}{
+		{
+			name: "no infrastructure config",
+		},
+		{
+			name:       "highly available AWS",
+			infra:      infra(configv1.AWSPlatformType, configv1.HighlyAvailableTopologyMode),
+			wantSpread: true,
+		},
+		{
+			name:  "single node AWS",
+			infra: infra(configv1.AWSPlatformType, configv1.SingleReplicaTopologyMode),
+		},
+		{
+			name:  "bare metal",
+			infra: infra(configv1.BareMetalPlatformType, configv1.HighlyAvailableTopologyMode),
+		},
+		{
+			name: "deprecated platform field",
+			infra: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{Platform: configv1.GCPPlatformType},
+			},
+			wantSpread: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tt.infra != nil {
+				require.NoError(t, indexer.Add(tt.infra))
+			}
+
+			got, err := getTopologySpreadConstraints(configv1listers.NewInfrastructureLister(indexer))
+			require.NoError(t, err)
+			if !tt.wantSpread {
+				require.Empty(t, got)
+				require.Equal(t, "topologySpread:", topologySpreadRolloutTrigger(got))
+				return
+			}
+			require.Len(t, got, 1)
+			require.Equal(t, corev1.LabelTopologyZone, got[0].TopologyKey)
+			require.Equal(t, corev1.ScheduleAnyway, got[0].WhenUnsatisfiable)
+			require.Equal(t, "oauth-openshift", got[0].LabelSelector.MatchLabels["app"])
+			require.Equal(t, "topologySpread:topology.kubernetes.io/zone/1/ScheduleAnyway", topologySpreadRolloutTrigger(got))
+		})
+	}
+}
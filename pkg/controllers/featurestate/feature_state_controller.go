@@ -0,0 +1,137 @@
+package featurestate
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/statedump"
+)
+
+// featureStateConfigMapName is the config map in the operator namespace that lists the
+// optional behaviors of the operator that are in effect, it is overwritten on every sync
+const featureStateConfigMapName = "authentication-operator-features"
+
+// featureStateController publishes which of the optional behaviors of the operator are
+// enabled so that the differences between clusters can be told apart at a glance
+type featureStateController struct {
+	operatorClient v1helpers.OperatorClient
+	ingressLister  configv1listers.IngressLister
+	configMaps     corev1client.ConfigMapsGetter
+}
+
+func NewFeatureStateController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	ingressInformer configinformers.IngressInformer,
+	configMaps corev1client.ConfigMapsGetter,
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &featureStateController{
+		operatorClient: operatorClient,
+		ingressLister:  ingressInformer.Lister(),
+		configMaps:     configMaps,
+	}
+
+	return factory.New().
+		WithInformers(
+			operatorClient.Informer(),
+			ingressInformer.Informer(),
+		).
+		WithFilteredEventsInformers(
+			common.NamesFilter(featureStateConfigMapName),
+			kubeInformersForNamespaces.InformersFor(common.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		).
+		WithSync(c.sync).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("FeatureStateController", eventRecorder.WithComponentSuffix("feature-state-controller"))
+}
+
+func (c *featureStateController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorConfigMeta, err := c.operatorClient.GetObjectMeta()
+	if err != nil {
+		return err
+	}
+	if common.IsReconciliationPaused(operatorConfigMeta) {
+		klog.V(4).Infof("reconciliation is paused, skipping sync")
+		return nil
+	}
+
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	ingressConfig, err := c.ingressLister.Get("cluster")
+	if err != nil {
+		return err
+	}
+
+	featureStateConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: common.OperatorNamespace,
+			Name:      featureStateConfigMapName,
+		},
+		Data: featureState(operatorConfigMeta, operatorSpec, ingressConfig),
+	}
+	// applying the config map on every sync reverts any manual edits
+	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMaps, syncCtx.Recorder(), featureStateConfigMap)
+	return err
+}
+
+// featureState lists the optional behaviors of the operator, the overrides are listed by
+// their keys only as their values are often long and they may carry sensitive data
+func featureState(operatorConfigMeta metav1.Object, operatorSpec *operatorv1.OperatorSpec, ingressConfig *configv1.Ingress) map[string]string {
+	_, stateDumpRequested := operatorConfigMeta.GetAnnotations()[statedump.StateDumpAnnotation]
+	customRouteHostname := common.GetCustomRouteHostname(ingressConfig, common.TargetNamespace, "oauth-openshift")
+
+	return map[string]string{
+		"managementState":            string(operatorSpec.ManagementState),
+		"logLevel":                   string(operatorSpec.LogLevel),
+		"operatorLogLevel":           string(operatorSpec.OperatorLogLevel),
+		"stateDumpRequested":         strconv.FormatBool(stateDumpRequested),
+		"customRouteHostname":        strconv.FormatBool(len(customRouteHostname) > 0),
+		"unsupportedConfigOverrides": strings.Join(unsupportedConfigOverrideKeys(operatorSpec), "\n"),
+	}
+}
+
+// unsupportedConfigOverrideKeys returns the sorted keys of the unsupportedConfigOverrides,
+// the keys of the nested objects are joined with a dot, e.g. "oauthServerDeployment.rolloutTrigger"
+func unsupportedConfigOverrideKeys(operatorSpec *operatorv1.OperatorSpec) []string {
+	overrides := map[string]interface{}{}
+	if err := common.DecodeUnsupportedConfigOverrides(operatorSpec, &overrides); err != nil {
+		return []string{"<undecodable>"}
+	}
+
+	keys := []string{}
+	for key, value := range overrides {
+		nested, ok := value.(map[string]interface{})
+		if !ok || len(nested) == 0 {
+			keys = append(keys, key)
+			continue
+		}
+		for nestedKey := range nested {
+			keys = append(keys, key+"."+nestedKey)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
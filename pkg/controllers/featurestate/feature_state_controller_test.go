@@ -0,0 +1,52 @@
+package featurestate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/statedump"
+)
+
+func TestFeatureState(t *testing.T) {
+	operatorConfigMeta := &metav1.ObjectMeta{
+		Annotations: map[string]string{statedump.StateDumpAnnotation: "1"},
+	}
+	operatorSpec := &operatorv1.OperatorSpec{
+		ManagementState: operatorv1.Managed,
+		LogLevel:        operatorv1.Debug,
+		UnsupportedConfigOverrides: runtime.RawExtension{
+			Raw: []byte(`{"oauthServerDeployment": {"rolloutTrigger": "contentHash", "extraEnv": [{"name": "GODEBUG", "value": "x509sha1=1"}]}, "oauthServerRoute": {}}`),
+		},
+	}
+	ingressConfig := &configv1.Ingress{
+		Spec: configv1.IngressSpec{
+			ComponentRoutes: []configv1.ComponentRouteSpec{{
+				Namespace: common.TargetNamespace,
+				Name:      "oauth-openshift",
+				Hostname:  "login.example.com",
+			}},
+		},
+	}
+
+	require.Equal(t, map[string]string{
+		"managementState":            "Managed",
+		"logLevel":                   "Debug",
+		"operatorLogLevel":           "",
+		"stateDumpRequested":         "true",
+		"customRouteHostname":        "true",
+		"unsupportedConfigOverrides": "oauthServerDeployment.extraEnv\noauthServerDeployment.rolloutTrigger\noauthServerRoute",
+	}, featureState(operatorConfigMeta, operatorSpec, ingressConfig))
+
+	defaults := featureState(&metav1.ObjectMeta{}, &operatorv1.OperatorSpec{}, &configv1.Ingress{})
+	require.Equal(t, "false", defaults["stateDumpRequested"])
+	require.Equal(t, "false", defaults["customRouteHostname"])
+	require.Empty(t, defaults["unsupportedConfigOverrides"])
+}
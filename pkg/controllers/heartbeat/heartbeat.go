@@ -0,0 +1,81 @@
+package heartbeat
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+)
+
+var lastSyncTimestamp = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Namespace:      "openshift",
+		Subsystem:      "authentication_operator",
+		Name:           "controller_last_sync_timestamp_seconds",
+		Help:           "Unix time of the last completed sync of the controller, it stops moving when the controller is stuck.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	legacyregistry.MustRegister(lastSyncTimestamp)
+}
+
+// defaultTracker records the syncs of the controllers of the running operator
+var defaultTracker = newTracker(time.Now)
+
+// Track records each completed sync of the named controller, failed syncs count too
+// as they still show that the controller makes progress
+func Track(controllerName string, sync factory.SyncFunc) factory.SyncFunc {
+	defaultTracker.register(controllerName)
+	return func(ctx context.Context, syncCtx factory.SyncContext) error {
+		defer defaultTracker.record(controllerName)
+		return sync(ctx, syncCtx)
+	}
+}
+
+type tracker struct {
+	lock      sync.Mutex
+	now       func() time.Time
+	lastSyncs map[string]time.Time
+}
+
+func newTracker(now func() time.Time) *tracker {
+	return &tracker{now: now, lastSyncs: map[string]time.Time{}}
+}
+
+// register starts the clock of a controller that has not synced yet
+func (t *tracker) register(controllerName string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, ok := t.lastSyncs[controllerName]; !ok {
+		t.lastSyncs[controllerName] = t.now()
+	}
+}
+
+func (t *tracker) record(controllerName string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	now := t.now()
+	t.lastSyncs[controllerName] = now
+	lastSyncTimestamp.WithLabelValues(controllerName).Set(float64(now.Unix()))
+}
+
+// stale returns the controllers that have not completed a sync for longer than staleAfter
+func (t *tracker) stale(staleAfter time.Duration) map[string]time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	now := t.now()
+	stale := map[string]time.Duration{}
+	for controllerName, lastSync := range t.lastSyncs {
+		if since := now.Sub(lastSync); since > staleAfter {
+			stale[controllerName] = since
+		}
+	}
+	return stale
+}
@@ -0,0 +1,70 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// staleSyncThreshold is well above the jittered one minute resync of the tracked
+// controllers so that a slow sync is not mistaken for a stuck one
+const staleSyncThreshold = 10 * time.Minute
+
+// heartbeatController reports the tracked controllers that stopped completing their
+// syncs, e.g. because they are stuck on a call that never returns
+type heartbeatController struct {
+	operatorClient v1helpers.OperatorClient
+	tracker        *tracker
+}
+
+func NewHeartbeatController(
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &heartbeatController{
+		operatorClient: operatorClient,
+		tracker:        defaultTracker,
+	}
+
+	return factory.New().
+		WithSync(c.sync).
+		ResyncEvery(time.Minute).
+		ToController("HeartbeatController", eventRecorder.WithComponentSuffix("heartbeat-controller"))
+}
+
+func (c *heartbeatController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient,
+		v1helpers.UpdateConditionFn(heartbeatCondition(c.tracker.stale(staleSyncThreshold))),
+	)
+	return err
+}
+
+func heartbeatCondition(stale map[string]time.Duration) operatorv1.OperatorCondition {
+	if len(stale) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   "OperatorHeartbeatDegraded",
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+
+	staleControllers := make([]string, 0, len(stale))
+	for controllerName, since := range stale {
+		staleControllers = append(staleControllers, fmt.Sprintf("%s has not completed a sync for %s", controllerName, since.Round(time.Second)))
+	}
+	sort.Strings(staleControllers)
+
+	return operatorv1.OperatorCondition{
+		Type:    "OperatorHeartbeatDegraded",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "ControllersNotSyncing",
+		Message: fmt.Sprintf("The following controllers appear to be stuck:\n%s", strings.Join(staleControllers, "\n")),
+	}
+}
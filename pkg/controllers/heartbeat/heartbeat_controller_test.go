@@ -0,0 +1,49 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+)
+
+func TestTrackerStale(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := newTracker(func() time.Time { return now })
+
+	tr.register("PayloadConfig")
+	tr.register("MetadataController")
+	require.Empty(t, tr.stale(staleSyncThreshold))
+
+	now = now.Add(5 * time.Minute)
+	tr.record("PayloadConfig")
+
+	now = now.Add(6 * time.Minute)
+	require.Equal(t, map[string]time.Duration{"MetadataController": 11 * time.Minute}, tr.stale(staleSyncThreshold))
+
+	condition := heartbeatCondition(tr.stale(staleSyncThreshold))
+	require.Equal(t, operatorv1.ConditionTrue, condition.Status)
+	require.Equal(t, "ControllersNotSyncing", condition.Reason)
+	require.Contains(t, condition.Message, "MetadataController has not completed a sync for 11m0s")
+	require.NotContains(t, condition.Message, "PayloadConfig")
+}
+
+func TestTrack(t *testing.T) {
+	defer func(original *tracker) { defaultTracker = original }(defaultTracker)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaultTracker = newTracker(func() time.Time { return now })
+
+	sync := Track("PayloadConfig", func(ctx context.Context, syncCtx factory.SyncContext) error {
+		return fmt.Errorf("failed")
+	})
+
+	now = now.Add(time.Hour)
+	require.Error(t, sync(context.Background(), nil))
+	// a failed sync still counts as a heartbeat
+	require.Empty(t, defaultTracker.stale(staleSyncThreshold))
+}
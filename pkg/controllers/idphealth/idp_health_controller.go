@@ -0,0 +1,241 @@
+package idphealth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	bootstrap "github.com/openshift/library-go/pkg/authentication/bootstrapauthenticator"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation/oauth"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
+)
+
+// identityProviderHealthController summarizes the health of the secrets and config maps
+// referenced by all the configured identity providers in a single condition
+type identityProviderHealthController struct {
+	authLister      configv1listers.AuthenticationLister
+	oauthLister     configv1listers.OAuthLister
+	configMapLister corev1listers.ConfigMapLister
+	secretLister    corev1listers.SecretLister
+	operatorClient  v1helpers.OperatorClient
+
+	bootstrapUserDataGetter bootstrap.BootstrapUserDataGetter
+}
+
+func NewIdentityProviderHealthController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	configInformers configinformers.SharedInformerFactory,
+	operatorClient v1helpers.OperatorClient,
+	bootstrapUserDataGetter bootstrap.BootstrapUserDataGetter,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	openshiftConfigInformers := kubeInformersForNamespaces.InformersFor("openshift-config")
+	kubeSystemInformers := kubeInformersForNamespaces.InformersFor("kube-system")
+
+	c := &identityProviderHealthController{
+		authLister:      configInformers.Config().V1().Authentications().Lister(),
+		oauthLister:     configInformers.Config().V1().OAuths().Lister(),
+		configMapLister: openshiftConfigInformers.Core().V1().ConfigMaps().Lister(),
+		secretLister:    openshiftConfigInformers.Core().V1().Secrets().Lister(),
+		operatorClient:  operatorClient,
+
+		bootstrapUserDataGetter: bootstrapUserDataGetter,
+	}
+
+	return factory.New().
+		WithInformers(
+			configInformers.Config().V1().Authentications().Informer(),
+			configInformers.Config().V1().OAuths().Informer(),
+			openshiftConfigInformers.Core().V1().ConfigMaps().Informer(),
+			openshiftConfigInformers.Core().V1().Secrets().Informer(),
+			// the bootstrap user lives in the kube-system/kubeadmin secret
+			kubeSystemInformers.Core().V1().Secrets().Informer(),
+		).
+		WithSync(heartbeat.Track("IdentityProviderHealthController", c.sync)).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("IdentityProviderHealthController", eventRecorder.WithComponentSuffix("identity-provider-health-controller"))
+}
+
+func (c *identityProviderHealthController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	oauthConfig, err := c.oauthLister.Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	var identityProviders []configv1.IdentityProvider
+	if oauthConfig != nil {
+		identityProviders = oauthConfig.Spec.IdentityProviders
+	}
+
+	bootstrapUserExists, err := c.bootstrapUserDataGetter.IsEnabled()
+	if err != nil {
+		return err
+	}
+
+	authConfig, err := c.authLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		authConfig = &configv1.Authentication{}
+	} else if err != nil {
+		return err
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient,
+		v1helpers.UpdateConditionFn(identityProvidersCondition(identityProviders, c.configMapLister, c.secretLister)),
+		v1helpers.UpdateConditionFn(loginPossibleCondition(identityProviders, bootstrapUserExists)),
+		v1helpers.UpdateConditionFn(insecureIdentityProvidersCondition(identityProviders)),
+		v1helpers.UpdateConditionFn(authenticationMethodsCondition(authConfig, identityProviders, bootstrapUserExists)),
+	)
+	return err
+}
+
+func identityProvidersCondition(identityProviders []configv1.IdentityProvider, cmLister corev1listers.ConfigMapLister, secretLister corev1listers.SecretLister) operatorv1.OperatorCondition {
+	brokenIDPs := []string{}
+	for i, idp := range identityProviders {
+		if errs := oauth.IdentityProviderReferences(&idp.IdentityProviderConfig, i).Validate(cmLister, secretLister); len(errs) > 0 {
+			brokenIDPs = append(brokenIDPs, fmt.Sprintf("%q: %v", idp.Name, utilerrors.NewAggregate(errs)))
+		}
+	}
+
+	healthy := len(identityProviders) - len(brokenIDPs)
+	if len(brokenIDPs) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:    "IdentityProviderReferencesDegraded",
+			Status:  operatorv1.ConditionFalse,
+			Reason:  "AsExpected",
+			Message: fmt.Sprintf("%d of %d identity providers are correctly configured", healthy, len(identityProviders)),
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:   "IdentityProviderReferencesDegraded",
+		Status: operatorv1.ConditionTrue,
+		Reason: "BrokenReferences",
+		Message: fmt.Sprintf("%d of %d identity providers are correctly configured, the following reference missing or invalid secrets or config maps:\n%s",
+			healthy, len(identityProviders), strings.Join(brokenIDPs, "\n")),
+	}
+}
+
+// insecureIdentityProvidersCondition is an informational condition that warns about
+// the LDAP identity providers that send the bind credentials and the user passwords
+// without TLS, LDAP is the only type that can opt out of TLS
+func insecureIdentityProvidersCondition(identityProviders []configv1.IdentityProvider) operatorv1.OperatorCondition {
+	insecureIDPs := []string{}
+	for _, idp := range identityProviders {
+		if idp.Type == configv1.IdentityProviderTypeLDAP && idp.LDAP != nil && idp.LDAP.Insecure {
+			insecureIDPs = append(insecureIDPs, fmt.Sprintf("%q connects to %s without TLS", idp.Name, idp.LDAP.URL))
+		}
+	}
+
+	if len(insecureIDPs) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   "IdentityProviderTLSInsecure",
+			Status: operatorv1.ConditionFalse,
+			Reason: "AsExpected",
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:   "IdentityProviderTLSInsecure",
+		Status: operatorv1.ConditionTrue,
+		Reason: "InsecureIdentityProviders",
+		Message: fmt.Sprintf("WARNING: the following identity providers skip TLS, user credentials are sent to them in clear text:\n%s",
+			strings.Join(insecureIDPs, "\n")),
+	}
+}
+
+// loginPossibleCondition is an informational condition that tells whether anyone can
+// log in through the oauth-server, it keeps running either way so that adding
+// an identity provider takes effect right away
+func loginPossibleCondition(identityProviders []configv1.IdentityProvider, bootstrapUserExists bool) operatorv1.OperatorCondition {
+	switch {
+	case len(identityProviders) > 0:
+		return operatorv1.OperatorCondition{
+			Type:   "OAuthServerLoginPossible",
+			Status: operatorv1.ConditionTrue,
+			Reason: "IdentityProvidersConfigured",
+		}
+	case bootstrapUserExists:
+		return operatorv1.OperatorCondition{
+			Type:    "OAuthServerLoginPossible",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "BootstrapUserOnly",
+			Message: "No identity providers are configured, only the kubeadmin bootstrap user can log in",
+		}
+	default:
+		return operatorv1.OperatorCondition{
+			Type:    "OAuthServerLoginPossible",
+			Status:  operatorv1.ConditionFalse,
+			Reason:  "NoIdentityProviders",
+			Message: "No identity providers are configured and the kubeadmin bootstrap user was removed, nobody can log in until an identity provider is added to oauths.config.openshift.io/cluster",
+		}
+	}
+}
+
+// authenticationMethodsCondition is an informational condition that summarizes how users
+// can authenticate right now, e.g. "kubeadmin + 2 identity providers". The bootstrap user
+// and the identity providers only count with the integrated oauth-server.
+func authenticationMethodsCondition(authConfig *configv1.Authentication, identityProviders []configv1.IdentityProvider, bootstrapUserExists bool) operatorv1.OperatorCondition {
+	switch authConfig.Spec.Type {
+	case configv1.AuthenticationTypeIntegratedOAuth, "":
+	case configv1.AuthenticationTypeNone:
+		message := "external authentication, the integrated oauth-server is not used"
+		if authConfig.Spec.WebhookTokenAuthenticator != nil && len(authConfig.Spec.WebhookTokenAuthenticator.KubeConfig.Name) > 0 {
+			message = "external authentication through a webhook token authenticator, the integrated oauth-server is not used"
+		}
+		return operatorv1.OperatorCondition{
+			Type:    "AuthenticationMethods",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "ExternalAuthentication",
+			Message: message,
+		}
+	default:
+		return operatorv1.OperatorCondition{
+			Type:    "AuthenticationMethods",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "ExternalAuthentication",
+			Message: fmt.Sprintf("external %s authentication, the integrated oauth-server is not used", authConfig.Spec.Type),
+		}
+	}
+
+	methods := []string{}
+	if bootstrapUserExists {
+		methods = append(methods, "kubeadmin")
+	}
+	switch len(identityProviders) {
+	case 0:
+	case 1:
+		methods = append(methods, "1 identity provider")
+	default:
+		methods = append(methods, fmt.Sprintf("%d identity providers", len(identityProviders)))
+	}
+
+	if len(methods) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:    "AuthenticationMethods",
+			Status:  operatorv1.ConditionFalse,
+			Reason:  "NoAuthenticationMethods",
+			Message: "none, no identity providers are configured and the kubeadmin bootstrap user was removed",
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    "AuthenticationMethods",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "IntegratedOAuth",
+		Message: strings.Join(methods, " + "),
+	}
+}
@@ -0,0 +1,255 @@
+package idphealth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestIdentityProvidersCondition(t *testing.T) {
+	htpasswdIDP := func(name, secretName string) configv1.IdentityProvider {
+		return configv1.IdentityProvider{
+			Name: name,
+			IdentityProviderConfig: configv1.IdentityProviderConfig{
+				Type:     configv1.IdentityProviderTypeHTPasswd,
+				HTPasswd: &configv1.HTPasswdIdentityProvider{FileData: configv1.SecretNameReference{Name: secretName}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		identityProviders []configv1.IdentityProvider
+		expectedStatus    operatorv1.ConditionStatus
+		expectedMessage   []string
+	}{
+		{
+			name:            "no identity providers",
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: []string{"0 of 0 identity providers"},
+		},
+		{
+			name:              "all identity providers are healthy",
+			identityProviders: []configv1.IdentityProvider{htpasswdIDP("first", "htpasswd"), htpasswdIDP("second", "htpasswd")},
+			expectedStatus:    operatorv1.ConditionFalse,
+			expectedMessage:   []string{"2 of 2 identity providers"},
+		},
+		{
+			name: "some identity providers are broken",
+			identityProviders: []configv1.IdentityProvider{
+				htpasswdIDP("first", "htpasswd"),
+				htpasswdIDP("missing", "missing-secret"),
+				htpasswdIDP("empty", "empty-secret"),
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: []string{"1 of 3 identity providers", `"missing"`, "missing-secret", `"empty"`, "empty-secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, secretIndexer.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: "htpasswd"},
+				Data:       map[string][]byte{configv1.HTPasswdDataKey: []byte("user:password")},
+			}))
+			require.NoError(t, secretIndexer.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: "empty-secret"},
+			}))
+
+			condition := identityProvidersCondition(
+				tt.identityProviders,
+				corev1listers.NewConfigMapLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+				corev1listers.NewSecretLister(secretIndexer),
+			)
+
+			require.Equal(t, "IdentityProviderReferencesDegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			for _, expected := range tt.expectedMessage {
+				require.True(t, strings.Contains(condition.Message, expected), "expected %q in %q", expected, condition.Message)
+			}
+		})
+	}
+}
+
+func TestInsecureIdentityProvidersCondition(t *testing.T) {
+	ldap := func(name string, insecure bool) configv1.IdentityProvider {
+		return configv1.IdentityProvider{
+			Name: name,
+			IdentityProviderConfig: configv1.IdentityProviderConfig{
+				Type: configv1.IdentityProviderTypeLDAP,
+				LDAP: &configv1.LDAPIdentityProvider{URL: "ldap://ldap.example.com/ou=users?uid", Insecure: insecure},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		identityProviders []configv1.IdentityProvider
+		expectedStatus    operatorv1.ConditionStatus
+		expectedMessage   []string
+	}{
+		{
+			name:           "no identity providers",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:              "secure LDAP",
+			identityProviders: []configv1.IdentityProvider{ldap("secure", false)},
+			expectedStatus:    operatorv1.ConditionFalse,
+		},
+		{
+			name:              "insecure LDAP",
+			identityProviders: []configv1.IdentityProvider{ldap("secure", false), ldap("plain", true)},
+			expectedStatus:    operatorv1.ConditionTrue,
+			expectedMessage:   []string{`"plain"`, "ldap://ldap.example.com/ou=users?uid"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := insecureIdentityProvidersCondition(tt.identityProviders)
+
+			require.Equal(t, "IdentityProviderTLSInsecure", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.NotContains(t, condition.Message, `"secure"`)
+			for _, expected := range tt.expectedMessage {
+				require.True(t, strings.Contains(condition.Message, expected), "expected %q in %q", expected, condition.Message)
+			}
+		})
+	}
+}
+
+func TestLoginPossibleCondition(t *testing.T) {
+	htpasswd := []configv1.IdentityProvider{{Name: "htpasswd"}}
+
+	tests := []struct {
+		name                string
+		identityProviders   []configv1.IdentityProvider
+		bootstrapUserExists bool
+		expectedStatus      operatorv1.ConditionStatus
+		expectedReason      string
+	}{
+		{
+			name:                "fresh install with the bootstrap user",
+			bootstrapUserExists: true,
+			expectedStatus:      operatorv1.ConditionTrue,
+			expectedReason:      "BootstrapUserOnly",
+		},
+		{
+			name:                "identity provider added",
+			identityProviders:   htpasswd,
+			bootstrapUserExists: true,
+			expectedStatus:      operatorv1.ConditionTrue,
+			expectedReason:      "IdentityProvidersConfigured",
+		},
+		{
+			name:              "bootstrap user removed",
+			identityProviders: htpasswd,
+			expectedStatus:    operatorv1.ConditionTrue,
+			expectedReason:    "IdentityProvidersConfigured",
+		},
+		{
+			name:           "identity providers removed as well",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "NoIdentityProviders",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := loginPossibleCondition(tt.identityProviders, tt.bootstrapUserExists)
+
+			require.Equal(t, "OAuthServerLoginPossible", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+		})
+	}
+}
+
+func TestAuthenticationMethodsCondition(t *testing.T) {
+	identityProviders := []configv1.IdentityProvider{{Name: "htpasswd"}, {Name: "ldap"}}
+
+	tests := []struct {
+		name                string
+		authSpec            configv1.AuthenticationSpec
+		identityProviders   []configv1.IdentityProvider
+		bootstrapUserExists bool
+		expectedStatus      operatorv1.ConditionStatus
+		expectedReason      string
+		expectedMessage     string
+	}{
+		{
+			name:                "fresh install",
+			bootstrapUserExists: true,
+			expectedStatus:      operatorv1.ConditionTrue,
+			expectedReason:      "IntegratedOAuth",
+			expectedMessage:     "kubeadmin",
+		},
+		{
+			name:                "bootstrap user and identity providers",
+			authSpec:            configv1.AuthenticationSpec{Type: configv1.AuthenticationTypeIntegratedOAuth},
+			identityProviders:   identityProviders,
+			bootstrapUserExists: true,
+			expectedStatus:      operatorv1.ConditionTrue,
+			expectedReason:      "IntegratedOAuth",
+			expectedMessage:     "kubeadmin + 2 identity providers",
+		},
+		{
+			name:              "single identity provider",
+			identityProviders: identityProviders[:1],
+			expectedStatus:    operatorv1.ConditionTrue,
+			expectedReason:    "IntegratedOAuth",
+			expectedMessage:   "1 identity provider",
+		},
+		{
+			name:           "nothing left",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "NoAuthenticationMethods",
+		},
+		{
+			name:                "external authentication ignores the integrated methods",
+			authSpec:            configv1.AuthenticationSpec{Type: configv1.AuthenticationTypeNone},
+			identityProviders:   identityProviders,
+			bootstrapUserExists: true,
+			expectedStatus:      operatorv1.ConditionTrue,
+			expectedReason:      "ExternalAuthentication",
+			expectedMessage:     "external authentication, the integrated oauth-server is not used",
+		},
+		{
+			name: "external webhook token authenticator",
+			authSpec: configv1.AuthenticationSpec{
+				Type:                      configv1.AuthenticationTypeNone,
+				WebhookTokenAuthenticator: &configv1.WebhookTokenAuthenticator{KubeConfig: configv1.SecretNameReference{Name: "webhook"}},
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "ExternalAuthentication",
+			expectedMessage: "external authentication through a webhook token authenticator, the integrated oauth-server is not used",
+		},
+		{
+			name:            "other external types",
+			authSpec:        configv1.AuthenticationSpec{Type: "OIDC"},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "ExternalAuthentication",
+			expectedMessage: "external OIDC authentication, the integrated oauth-server is not used",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := authenticationMethodsCondition(&configv1.Authentication{Spec: tt.authSpec}, tt.identityProviders, tt.bootstrapUserExists)
+
+			require.Equal(t, "AuthenticationMethods", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+			if len(tt.expectedMessage) > 0 {
+				require.Equal(t, tt.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
@@ -2,17 +2,20 @@ package metadata
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -27,6 +30,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
 )
 
 // knownConditionNames lists all condition types used by this controller.
@@ -36,15 +40,28 @@ var knownConditionNames = sets.NewString(
 	"IngressConfigDegraded",
 	"AuthConfigDegraded",
 	"OAuthSystemMetadataDegraded",
+	"OAuthEndpointConfigDegraded",
+	"OAuthMetadataProgressing",
+	"OAuthMetadataPublishProgressing",
+)
+
+// applyConditionNames lists the conditions that report on the objects this controller
+// applies, they keep their last state while the reconciliation is paused
+var applyConditionNames = sets.NewString(
+	"AuthConfigDegraded",
+	"OAuthSystemMetadataDegraded",
+	"OAuthEndpointConfigDegraded",
+	"OAuthMetadataProgressing",
 )
 
 type metadataController struct {
-	ingressLister  configv1listers.IngressLister
-	route          routeclient.RouteInterface
-	secretLister   corev1listers.SecretLister
-	configMaps     corev1client.ConfigMapsGetter
-	authentication configv1client.AuthenticationInterface
-	operatorClient v1helpers.OperatorClient
+	ingressLister   configv1listers.IngressLister
+	route           routeclient.RouteInterface
+	secretLister    corev1listers.SecretLister
+	configMapLister corev1listers.ConfigMapLister
+	configMaps      corev1client.ConfigMapsGetter
+	authentication  configv1client.AuthenticationInterface
+	operatorClient  v1helpers.OperatorClient
 }
 
 // NewMetadataController assure that ingress configuration is available to determine the domain suffix that this controller use to create
@@ -55,53 +72,111 @@ func NewMetadataController(kubeInformersForTargetNamespace informers.SharedInfor
 	configMaps corev1client.ConfigMapsGetter, route routeclient.RouteInterface, authentication configv1client.AuthenticationInterface, operatorClient v1helpers.OperatorClient,
 	recorder events.Recorder) factory.Controller {
 	c := &metadataController{
-		ingressLister:  configInformer.Config().V1().Ingresses().Lister(),
-		secretLister:   kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
-		configMaps:     configMaps,
-		route:          route,
-		authentication: authentication,
-		operatorClient: operatorClient,
+		ingressLister:   configInformer.Config().V1().Ingresses().Lister(),
+		secretLister:    kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
+		configMapLister: kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Lister(),
+		configMaps:      configMaps,
+		route:           route,
+		authentication:  authentication,
+		operatorClient:  operatorClient,
 	}
 	return factory.New().WithInformers(
 		kubeInformersForTargetNamespace.Core().V1().Secrets().Informer(),
+		kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Informer(),
 		configInformer.Config().V1().Authentications().Informer(),
 		configInformer.Config().V1().Ingresses().Informer(),
 		routeInformer.Route().V1().Routes().Informer(),
-	).ResyncEvery(wait.Jitter(time.Minute, 1.0)).WithSync(c.sync).ToController("MetadataController", recorder.WithComponentSuffix("metadata-controller"))
+	).ResyncEvery(wait.Jitter(time.Minute, 1.0)).WithSync(heartbeat.Track("MetadataController", c.sync)).ToController("MetadataController", recorder.WithComponentSuffix("metadata-controller"))
 }
 
 func (c *metadataController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	paused, err := common.IsOperatorReconciliationPaused(c.operatorClient)
+	if err != nil {
+		return err
+	}
+	if paused {
+		klog.V(4).Infof("reconciliation is paused, skipping the OAuth metadata and auth config updates")
+		return common.UpdateStagedControllerConditions(ctx, c.operatorClient, knownConditionNames.Difference(applyConditionNames), sets.NewString(),
+			[]operatorv1.OperatorCondition{publishedOAuthMetadataCondition(ctx, c.configMaps)})
+	}
+
 	foundConditions := []operatorv1.OperatorCondition{}
 
 	foundConditions = append(foundConditions, c.handleOAuthMetadataConfigMap(ctx, syncCtx.Recorder())...)
 
-	if len(foundConditions) == 0 {
+	// a route host change only delays the metadata, the auth config can still be handled
+	metadataFailed := false
+	for _, condition := range foundConditions {
+		if strings.HasSuffix(condition.Type, "Degraded") {
+			metadataFailed = true
+		}
+	}
+
+	skippedConditions := sets.NewString()
+	if !metadataFailed {
 		foundConditions = append(foundConditions, c.handleAuthConfig(ctx)...)
+		foundConditions = append(foundConditions, publishedOAuthMetadataCondition(ctx, c.configMaps))
+	} else {
+		skippedConditions.Insert("AuthConfigDegraded", "OAuthMetadataPublishProgressing")
 	}
 
-	return common.UpdateControllerConditions(ctx, c.operatorClient, knownConditionNames, foundConditions)
+	return common.UpdateStagedControllerConditions(ctx, c.operatorClient, knownConditionNames, skippedConditions, foundConditions)
 }
 
 func (c *metadataController) handleOAuthMetadataConfigMap(ctx context.Context, recorder events.Recorder) []operatorv1.OperatorCondition {
+	routeHost, failedConditions := c.getRouteHost(ctx)
+	if len(failedConditions) > 0 {
+		return failedConditions
+	}
+	if failedConditions := c.applyOAuthMetadata(ctx, recorder, routeHost); len(failedConditions) > 0 {
+		return failedConditions
+	}
+
+	// the route host may have changed while the metadata were being applied, the well-known
+	// check would then see the metadata and the route disagree until the next sync
+	finalRouteHost, failedConditions := c.getRouteHost(ctx)
+	if len(failedConditions) > 0 {
+		return failedConditions
+	}
+	if finalRouteHost == routeHost {
+		return nil
+	}
+	if failedConditions := c.applyOAuthMetadata(ctx, recorder, finalRouteHost); len(failedConditions) > 0 {
+		return failedConditions
+	}
+	return []operatorv1.OperatorCondition{{
+		Type:    "OAuthMetadataProgressing",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "RouteHostChanged",
+		Message: fmt.Sprintf("The route host changed from %q to %q while the OAuth metadata were being applied, the metadata were re-applied for the new host", routeHost, finalRouteHost),
+	}}
+}
+
+func (c *metadataController) getRouteHost(ctx context.Context) (string, []operatorv1.OperatorCondition) {
 	route, err := c.route.Get(ctx, "oauth-openshift", metav1.GetOptions{})
 	if err != nil {
-		return []operatorv1.OperatorCondition{{
+		return "", []operatorv1.OperatorCondition{{
 			Type:    "RouteDegraded",
 			Status:  operatorv1.ConditionTrue,
 			Reason:  "FailedGet",
-			Message: fmt.Sprintf("Unable to get required route %s/%s: %v", "openshift-authentication", "oauth-openshift", err),
+			Message: fmt.Sprintf("Unable to get required route %s/%s: %v", common.TargetNamespace, "oauth-openshift", err),
 		}}
 	}
 	if len(route.Status.Ingress) == 0 || len(route.Status.Ingress[0].Host) == 0 {
-		return []operatorv1.OperatorCondition{{
+		return "", []operatorv1.OperatorCondition{{
 			Type:    "RouteDegraded",
 			Status:  operatorv1.ConditionTrue,
 			Reason:  "NotReady",
-			Message: fmt.Sprintf("Route %s/%s is not ready: The ingress host is empty in route status", "openshift-authentication", "oauth-openshift"),
+			Message: fmt.Sprintf("Route %s/%s is not ready: The ingress host is empty in route status", common.TargetNamespace, "oauth-openshift"),
 		}}
 	}
+	return route.Status.Ingress[0].Host, nil
+}
+
+func (c *metadataController) applyOAuthMetadata(ctx context.Context, recorder events.Recorder, routeHost string) []operatorv1.OperatorCondition {
 	// make sure API server sees our metadata as soon as we've got a route with a host
-	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMaps, recorder, getOAuthMetadataConfigMap(route.Status.Ingress[0].Host)); err != nil {
+	applied, _, err := resourceapply.ApplyConfigMap(ctx, c.configMaps, recorder, getOAuthMetadataConfigMap(routeHost))
+	if err != nil {
 		return []operatorv1.OperatorCondition{{
 			Type:    "OAuthSystemMetadataDegraded",
 			Status:  operatorv1.ConditionTrue,
@@ -109,14 +184,52 @@ func (c *metadataController) handleOAuthMetadataConfigMap(ctx context.Context, r
 			Message: fmt.Sprintf("The ingress config domain cannot be empty"),
 		}}
 	}
+	// the apply above should have done it but stale metadata would break the login flows,
+	// the metadata are read back once the informer caught up with the apply, the config map
+	// events trigger another sync until then
+	if existing, err := c.configMapLister.ConfigMaps(applied.Namespace).Get(applied.Name); err == nil && existing.ResourceVersion == applied.ResourceVersion {
+		updated, err := ensureOAuthMetadataMatchesRoute(ctx, c.configMaps, recorder, existing, routeHost)
+		if err != nil {
+			return []operatorv1.OperatorCondition{{
+				Type:    "OAuthSystemMetadataDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "StaleMetadata",
+				Message: fmt.Sprintf("Unable to update the stale OAuth metadata: %v", err),
+			}}
+		}
+		if err := verifyOAuthMetadata(updated, routeHost); err != nil {
+			return []operatorv1.OperatorCondition{{
+				Type:    "OAuthSystemMetadataDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "MetadataNotApplied",
+				Message: err.Error(),
+			}}
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return []operatorv1.OperatorCondition{{
+			Type:    "OAuthSystemMetadataDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "MetadataNotApplied",
+			Message: fmt.Sprintf("Unable to verify the OAuth metadata in %s/%s: %v", applied.Namespace, applied.Name, err),
+		}}
+	}
+	// publish the host we settled on so that consumers don't need to read the route
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMaps, recorder, getOAuthEndpointConfigMap(routeHost)); err != nil {
+		return []operatorv1.OperatorCondition{{
+			Type:    "OAuthEndpointConfigDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "ApplyFailed",
+			Message: fmt.Sprintf("Unable to apply %s/%s config map: %v", "openshift-config-managed", "oauth-openshift-endpoint", err),
+		}}
+	}
 	return nil
 }
 
 // FIXME: we need to handle Authentication config object properly, namely:
-// - honor Type field being set to none and don't create the OSIN
-//   deployment in that case
-// - the WebhookTokenAuthenticators field is currently not being handled
-//   anywhere
+//   - honor Type field being set to none and don't create the OSIN
+//     deployment in that case
+//   - the WebhookTokenAuthenticators field is currently not being handled
+//     anywhere
 //
 // Note that the configMap from the reference in the OAuthMetadata field is
 // used to fill the data in the /.well-known/oauth-authorization-server
@@ -188,7 +301,7 @@ func getOAuthMetadataConfigMap(routeHost string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "v4-0-config-system-metadata",
-			Namespace: "openshift-authentication",
+			Namespace: common.TargetNamespace,
 			Labels: map[string]string{
 				"app": "oauth-openshift",
 			},
@@ -199,3 +312,96 @@ func getOAuthMetadataConfigMap(routeHost string) *corev1.ConfigMap {
 		},
 	}
 }
+
+// ensureOAuthMetadataMatchesRoute compares the issuer in the stored OAuth metadata with
+// the current route host and overwrites the metadata if they differ, it returns the
+// config map as it is stored afterwards
+func ensureOAuthMetadataMatchesRoute(ctx context.Context, configMaps corev1client.ConfigMapsGetter, recorder events.Recorder, existing *corev1.ConfigMap, routeHost string) (*corev1.ConfigMap, error) {
+	expected := getOAuthMetadataConfigMap(routeHost)
+
+	metadata := struct {
+		Issuer string `json:"issuer"`
+	}{}
+	if err := json.Unmarshal([]byte(existing.Data[configv1.OAuthMetadataKey]), &metadata); err == nil && metadata.Issuer == "https://"+routeHost {
+		return existing, nil
+	}
+
+	existingCopy := existing.DeepCopy()
+	existingCopy.Data = expected.Data
+	updated, err := configMaps.ConfigMaps(expected.Namespace).Update(ctx, existingCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	recorder.Warningf("OAuthMetadataCorrected", "The issuer in the %s/%s config map did not match the route host %q, the metadata were overwritten", expected.Namespace, expected.Name, routeHost)
+
+	return updated, nil
+}
+
+// verifyOAuthMetadata checks the OAuth metadata read back after they were applied, an apply
+// that succeeds but keeps the old content would otherwise go unnoticed
+func verifyOAuthMetadata(existing *corev1.ConfigMap, routeHost string) error {
+	expected := getOAuthMetadataConfigMap(routeHost)
+
+	if existing.Data[configv1.OAuthMetadataKey] != expected.Data[configv1.OAuthMetadataKey] {
+		return fmt.Errorf("the OAuth metadata in %s/%s still differ from the metadata for the route host %q after they were applied", expected.Namespace, expected.Name, routeHost)
+	}
+	return nil
+}
+
+// publishedOAuthMetadataCondition compares the OAuth metadata the kube-apiservers serve on their
+// well-known endpoint with the ones the operator applied. The kube-apiserver operator reads them
+// from the openshift-config-managed config map referenced in the status of the authentication
+// config, the resource sync controller copies them there.
+func publishedOAuthMetadataCondition(ctx context.Context, configMaps corev1client.ConfigMapsGetter) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthMetadataPublishProgressing",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	applied, err := configMaps.ConfigMaps(common.TargetNamespace).Get(ctx, "v4-0-config-system-metadata", metav1.GetOptions{})
+	if err != nil {
+		condition.Status = operatorv1.ConditionUnknown
+		condition.Reason = "GetFailed"
+		condition.Message = fmt.Sprintf("Unable to get the %s/%s config map: %v", common.TargetNamespace, "v4-0-config-system-metadata", err)
+		return condition
+	}
+
+	published, err := configMaps.ConfigMaps("openshift-config-managed").Get(ctx, "oauth-openshift", metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "MetadataNotPublished"
+		condition.Message = "The OAuth metadata were not copied to openshift-config-managed/oauth-openshift yet, the kube-apiservers serve no OAuth metadata on their well-known endpoint"
+	case err != nil:
+		condition.Status = operatorv1.ConditionUnknown
+		condition.Reason = "GetFailed"
+		condition.Message = fmt.Sprintf("Unable to get the openshift-config-managed/oauth-openshift config map: %v", err)
+	case published.Data[configv1.OAuthMetadataKey] != applied.Data[configv1.OAuthMetadataKey]:
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "PublishedMetadataStale"
+		condition.Message = fmt.Sprintf("The OAuth metadata in openshift-config-managed/oauth-openshift differ from those in %s/%s, the kube-apiservers serve stale OAuth metadata on their well-known endpoint", common.TargetNamespace, "v4-0-config-system-metadata")
+	}
+	return condition
+}
+
+// getOAuthEndpointConfigMap returns a config map that publishes the effective
+// OAuth server route host and the issuer derived from it, along with the OAuth
+// metadata the well-known endpoint of the kube-apiserver is expected to serve so
+// that external tooling can compare them with the live document.
+func getOAuthEndpointConfigMap(routeHost string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oauth-openshift-endpoint",
+			Namespace: "openshift-config-managed",
+			Labels: map[string]string{
+				"app": "oauth-openshift",
+			},
+		},
+		Data: map[string]string{
+			"routeHost":               routeHost,
+			"issuer":                  "https://" + routeHost,
+			configv1.OAuthMetadataKey: getOAuthMetadata(routeHost),
+		},
+	}
+}
@@ -0,0 +1,298 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+// fakeRouteClient serves the oauth-openshift route with the next of the hosts on each get
+type fakeRouteClient struct {
+	routeclient.RouteInterface
+	hosts []string
+	gets  int
+}
+
+func (c *fakeRouteClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*routev1.Route, error) {
+	host := c.hosts[len(c.hosts)-1]
+	if c.gets < len(c.hosts) {
+		host = c.hosts[c.gets]
+	}
+	c.gets++
+	return &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: name},
+		Status:     routev1.RouteStatus{Ingress: []routev1.RouteIngress{{Host: host}}},
+	}, nil
+}
+
+// fakeConfigMapLister reads the config maps from the client, i.e. an informer that caught up
+type fakeConfigMapLister struct {
+	corev1listers.ConfigMapLister
+	client kubernetes.Interface
+}
+
+func (l *fakeConfigMapLister) ConfigMaps(namespace string) corev1listers.ConfigMapNamespaceLister {
+	return &fakeConfigMapNamespaceLister{client: l.client, namespace: namespace}
+}
+
+type fakeConfigMapNamespaceLister struct {
+	corev1listers.ConfigMapNamespaceLister
+	client    kubernetes.Interface
+	namespace string
+}
+
+func (l *fakeConfigMapNamespaceLister) Get(name string) (*corev1.ConfigMap, error) {
+	return l.client.CoreV1().ConfigMaps(l.namespace).Get(context.Background(), name, metav1.GetOptions{})
+}
+
+func TestHandleOAuthMetadataConfigMap(t *testing.T) {
+	tests := []struct {
+		name                string
+		routeHosts          []string
+		expectedConditions  []operatorv1.OperatorCondition
+		expectedMetadataFor string
+	}{
+		{
+			name:                "stable route host",
+			routeHosts:          []string{"oauth-openshift.apps.example.com"},
+			expectedMetadataFor: "oauth-openshift.apps.example.com",
+		},
+		{
+			name:       "route host changed while applying the metadata",
+			routeHosts: []string{"oauth-openshift.apps.example.com", "login.example.com"},
+			expectedConditions: []operatorv1.OperatorCondition{{
+				Type:    "OAuthMetadataProgressing",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "RouteHostChanged",
+				Message: `The route host changed from "oauth-openshift.apps.example.com" to "login.example.com" while the OAuth metadata were being applied, the metadata were re-applied for the new host`,
+			}},
+			expectedMetadataFor: "login.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			c := &metadataController{
+				configMapLister: &fakeConfigMapLister{client: kubeClient},
+				configMaps:      kubeClient.CoreV1(),
+				route:           &fakeRouteClient{hosts: tt.routeHosts},
+			}
+
+			conditions := c.handleOAuthMetadataConfigMap(context.Background(), events.NewInMemoryRecorder("test"))
+			require.Equal(t, tt.expectedConditions, conditions)
+
+			metadata, err := kubeClient.CoreV1().ConfigMaps("openshift-authentication").Get(context.Background(), "v4-0-config-system-metadata", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, getOAuthMetadata(tt.expectedMetadataFor), metadata.Data[configv1.OAuthMetadataKey])
+
+			endpoint, err := kubeClient.CoreV1().ConfigMaps("openshift-config-managed").Get(context.Background(), "oauth-openshift-endpoint", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedMetadataFor, endpoint.Data["routeHost"])
+			require.Equal(t, metadata.Data[configv1.OAuthMetadataKey], endpoint.Data[configv1.OAuthMetadataKey])
+		})
+	}
+}
+
+func TestSyncWhilePaused(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	staleMetadata := operatorv1.OperatorCondition{
+		Type:    "OAuthSystemMetadataDegraded",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "StaleMetadata",
+		Message: "Unable to update the stale OAuth metadata",
+	}
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+		&metav1.ObjectMeta{Name: "cluster", Annotations: map[string]string{common.ReconciliationPausedAnnotation: ""}},
+		&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		&operatorv1.OperatorStatus{Conditions: []operatorv1.OperatorCondition{staleMetadata}},
+		nil,
+	)
+	c := &metadataController{
+		configMapLister: &fakeConfigMapLister{client: kubeClient},
+		configMaps:      kubeClient.CoreV1(),
+		route:           &fakeRouteClient{hosts: []string{"oauth-openshift.apps.example.com"}},
+		operatorClient:  operatorClient,
+	}
+
+	require.NoError(t, c.sync(context.Background(), factory.NewSyncContext("testctx", events.NewInMemoryRecorder("test"))))
+
+	for _, action := range kubeClient.Actions() {
+		require.Equal(t, "get", action.GetVerb(), "nothing must be applied while paused: %v", action)
+	}
+
+	_, status, _, err := operatorClient.GetOperatorState()
+	require.NoError(t, err)
+	// the conditions of the skipped applies keep their state, the checks are refreshed
+	condition := v1helpers.FindOperatorCondition(status.Conditions, "OAuthSystemMetadataDegraded")
+	require.NotNil(t, condition)
+	require.Equal(t, staleMetadata.Message, condition.Message)
+	condition = v1helpers.FindOperatorCondition(status.Conditions, "OAuthMetadataPublishProgressing")
+	require.NotNil(t, condition)
+	require.Equal(t, "GetFailed", condition.Reason)
+}
+
+func TestEnsureOAuthMetadataMatchesRoute(t *testing.T) {
+	tests := []struct {
+		name          string
+		storedHost    string
+		routeHost     string
+		expectedEvent bool
+	}{
+		{
+			name:       "metadata match the route",
+			storedHost: "oauth-openshift.apps.example.com",
+			routeHost:  "oauth-openshift.apps.example.com",
+		},
+		{
+			name:          "route host changed",
+			storedHost:    "oauth-openshift.apps.example.com",
+			routeHost:     "login.example.com",
+			expectedEvent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := getOAuthMetadataConfigMap(tt.storedHost)
+			kubeClient := fake.NewSimpleClientset(stored)
+			recorder := events.NewInMemoryRecorder("test")
+
+			updated, err := ensureOAuthMetadataMatchesRoute(context.Background(), kubeClient.CoreV1(), recorder, stored, tt.routeHost)
+			require.NoError(t, err)
+			require.Equal(t, getOAuthMetadata(tt.routeHost), updated.Data[configv1.OAuthMetadataKey])
+
+			cm, err := kubeClient.CoreV1().ConfigMaps("openshift-authentication").Get(context.Background(), "v4-0-config-system-metadata", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, getOAuthMetadata(tt.routeHost), cm.Data[configv1.OAuthMetadataKey])
+
+			correctedEvents := 0
+			for _, event := range recorder.Events() {
+				if event.Reason == "OAuthMetadataCorrected" {
+					correctedEvents++
+				}
+			}
+			if tt.expectedEvent {
+				require.Equal(t, 1, correctedEvents)
+			} else {
+				require.Zero(t, correctedEvents)
+			}
+		})
+	}
+}
+
+func TestVerifyOAuthMetadata(t *testing.T) {
+	tests := []struct {
+		name          string
+		storedHost    string
+		routeHost     string
+		expectedError bool
+	}{
+		{
+			name:       "metadata applied",
+			storedHost: "oauth-openshift.apps.example.com",
+			routeHost:  "oauth-openshift.apps.example.com",
+		},
+		{
+			name:          "stale metadata kept",
+			storedHost:    "oauth-openshift.apps.example.com",
+			routeHost:     "login.example.com",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyOAuthMetadata(getOAuthMetadataConfigMap(tt.storedHost), tt.routeHost)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOAuthMetadataConfigMapLocation(t *testing.T) {
+	expected := getOAuthMetadataConfigMap("oauth-openshift.apps.example.com")
+	// the resource sync controller copies it from there to openshift-config-managed/oauth-openshift
+	require.Equal(t, "openshift-authentication", expected.Namespace)
+	require.Equal(t, "v4-0-config-system-metadata", expected.Name)
+	require.Equal(t, map[string]string{"app": "oauth-openshift"}, expected.Labels)
+
+	// drifted labels are restored on apply
+	drifted := expected.DeepCopy()
+	drifted.Labels = map[string]string{"owner": "someone"}
+	kubeClient := fake.NewSimpleClientset(drifted)
+	c := &metadataController{
+		configMapLister: &fakeConfigMapLister{client: kubeClient},
+		configMaps:      kubeClient.CoreV1(),
+		route:           &fakeRouteClient{hosts: []string{"oauth-openshift.apps.example.com"}},
+	}
+	require.Empty(t, c.handleOAuthMetadataConfigMap(context.Background(), events.NewInMemoryRecorder("test")))
+
+	cm, err := kubeClient.CoreV1().ConfigMaps("openshift-authentication").Get(context.Background(), "v4-0-config-system-metadata", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "oauth-openshift", cm.Labels["app"])
+}
+
+func TestPublishedOAuthMetadataCondition(t *testing.T) {
+	published := func(host string) *corev1.ConfigMap {
+		cm := getOAuthMetadataConfigMap(host)
+		cm.Namespace, cm.Name = "openshift-config-managed", "oauth-openshift"
+		return cm
+	}
+
+	tests := []struct {
+		name           string
+		published      *corev1.ConfigMap
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "published",
+			published:      published("oauth-openshift.apps.example.com"),
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "not published yet",
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "MetadataNotPublished",
+		},
+		{
+			name:           "stale published metadata",
+			published:      published("login.example.com"),
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "PublishedMetadataStale",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(getOAuthMetadataConfigMap("oauth-openshift.apps.example.com"))
+			if tt.published != nil {
+				_, err := kubeClient.CoreV1().ConfigMaps("openshift-config-managed").Create(context.Background(), tt.published, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			condition := publishedOAuthMetadataCondition(context.Background(), kubeClient.CoreV1())
+			require.Equal(t, "OAuthMetadataPublishProgressing", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package oauthclientscontroller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+// minAccessTokenInactivityTimeoutSeconds is the lowest non-zero inactivity timeout accepted
+// by the oauth-apiserver for oauth clients
+const minAccessTokenInactivityTimeoutSeconds = 300
+
+// managedOAuthClients are the oauth clients reconciled by this controller
+var managedOAuthClients = sets.NewString(browserClientName, cliClientName)
+
+// oauthClientOverride are the token settings of a managed oauth client that can be set
+// in the "oauthClients" key of the operator's unsupportedConfigOverrides
+type oauthClientOverride struct {
+	// AccessTokenInactivityTimeoutSeconds overrides the cluster-wide inactivity timeout
+	// for the tokens granted to the client, 0 means the tokens never time out
+	AccessTokenInactivityTimeoutSeconds *int32 `json:"accessTokenInactivityTimeoutSeconds,omitempty"`
+}
+
+func getOAuthClientOverrides(spec *operatorv1.OperatorSpec) (map[string]oauthClientOverride, error) {
+	unsupportedConfig := struct {
+		OAuthClients map[string]oauthClientOverride `json:"oauthClients"`
+	}{}
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode unsupportedConfigOverrides: %w", err)
+	}
+
+	for name, override := range unsupportedConfig.OAuthClients {
+		if !managedOAuthClients.Has(name) {
+			return nil, fmt.Errorf("oauthClients references the unknown oauth client %q, only the oauth clients managed by the operator (%v) can be set here, the other ones are configured directly",
+				name, managedOAuthClients.List())
+		}
+		if timeout := override.AccessTokenInactivityTimeoutSeconds; timeout != nil && *timeout != 0 && *timeout < minAccessTokenInactivityTimeoutSeconds {
+			return nil, fmt.Errorf("oauthClients.%s.accessTokenInactivityTimeoutSeconds must be either 0 or at least %d, got %d",
+				name, minAccessTokenInactivityTimeoutSeconds, *timeout)
+		}
+	}
+
+	return unsupportedConfig.OAuthClients, nil
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -12,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
 	oauthv1 "github.com/openshift/api/oauth/v1"
@@ -30,9 +33,20 @@ import (
 
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/customroute"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
 )
 
+const (
+	browserClientName = "openshift-browser-client"
+	cliClientName     = "openshift-challenging-client"
+)
+
+// inactivityTimeoutAnnotation records the inactivity timeout the operator set on a client
+// from the overrides so that it is cleared once the override is removed
+const inactivityTimeoutAnnotation = "authentication.operator.openshift.io/access-token-inactivity-timeout-seconds"
+
 type oauthsClientsController struct {
+	operatorClient    v1helpers.OperatorClient
 	oauthClientClient oauthclient.OAuthClientInterface
 
 	oauthClientLister oauthv1listers.OAuthClientLister
@@ -49,6 +63,7 @@ func NewOAuthClientsController(
 	eventRecorder events.Recorder,
 ) factory.Controller {
 	c := &oauthsClientsController{
+		operatorClient:    operatorClient,
 		oauthClientClient: oauthsClientClient,
 
 		oauthClientLister: oauthInformers.Oauth().V1().OAuthClients().Lister(),
@@ -57,39 +72,57 @@ func NewOAuthClientsController(
 	}
 
 	return factory.New().
-		WithSync(c.sync).
+		WithSync(heartbeat.Track("OAuthClientsController", c.sync)).
 		WithSyncDegradedOnError(operatorClient).
 		WithFilteredEventsInformers(
-			common.NamesFilter("openshift-browser-client", "openshift-challenging-client"),
+			common.NamesFilter(browserClientName, cliClientName),
 			oauthInformers.Oauth().V1().OAuthClients().Informer(),
 		).
 		WithFilteredEventsInformers(
 			common.NamesFilter("oauth-openshift"),
 			routeInformers.Route().V1().Routes().Informer(),
 		).
-		WithInformers(ingressInformers.Config().V1().Ingresses().Informer()).
+		WithInformers(
+			operatorClient.Informer(),
+			ingressInformers.Config().V1().Ingresses().Informer(),
+		).
 		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
 		ToController("OAuthClientsController", eventRecorder.WithComponentSuffix("oauth-clients-controller"))
 }
 
 func (c *oauthsClientsController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	if paused, err := common.IsOperatorReconciliationPaused(c.operatorClient); err != nil {
+		return err
+	} else if paused {
+		klog.V(4).Infof("reconciliation is paused, skipping sync")
+		return nil
+	}
+
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	clientOverrides, err := getOAuthClientOverrides(operatorSpec)
+	if err != nil {
+		return err
+	}
+
 	ingress, err := c.getIngressConfig()
 	if err != nil {
 		return err
 	}
 	ingressConfigCopy := ingress.DeepCopy()
 
+	// the default host may be templated, the route then carries the expected one
 	hostname := common.GetCustomRouteHostname(ingressConfigCopy, customroute.OAuthComponentRouteNamespace, customroute.OAuthComponentRouteName)
-	if hostname == "" {
-		hostname = "oauth-openshift." + ingress.Spec.Domain
-	}
 
 	routeHost, err := c.getCanonicalRouteHost(hostname)
 	if err != nil {
 		return err
 	}
 
-	return c.ensureBootstrappedOAuthClients(ctx, "https://"+routeHost)
+	return c.ensureBootstrappedOAuthClients(ctx, syncCtx.Recorder(), "https://"+routeHost, clientOverrides)
 }
 
 func (c *oauthsClientsController) getIngressConfig() (*configv1.Ingress, error) {
@@ -97,17 +130,24 @@ func (c *oauthsClientsController) getIngressConfig() (*configv1.Ingress, error)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get cluster ingress config: %v", err)
 	}
-	if len(ingress.Spec.Domain) == 0 {
-		return nil, fmt.Errorf("the ingress config domain cannot be empty")
+	domain, err := common.NormalizeIngressDomain(ingress.Spec.Domain)
+	if err != nil {
+		return nil, err
 	}
+
+	ingress = ingress.DeepCopy()
+	ingress.Spec.Domain = domain
 	return ingress, nil
 }
 
 func (c *oauthsClientsController) getCanonicalRouteHost(expectedHost string) (string, error) {
-	route, err := c.routeLister.Routes("openshift-authentication").Get("oauth-openshift")
+	route, err := c.routeLister.Routes(common.TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		return "", err
 	}
+	if len(expectedHost) == 0 {
+		expectedHost = route.Spec.Host
+	}
 
 	routeHost, _, err := routeapihelpers.IngressURI(route, expectedHost)
 	if err != nil {
@@ -116,26 +156,30 @@ func (c *oauthsClientsController) getCanonicalRouteHost(expectedHost string) (st
 	return routeHost.Host, nil
 }
 
-func (c *oauthsClientsController) ensureBootstrappedOAuthClients(ctx context.Context, masterPublicURL string) error {
+func (c *oauthsClientsController) ensureBootstrappedOAuthClients(ctx context.Context, recorder events.Recorder, masterPublicURL string, clientOverrides map[string]oauthClientOverride) error {
 	browserClient := oauthv1.OAuthClient{
-		ObjectMeta:            metav1.ObjectMeta{Name: "openshift-browser-client"},
+		ObjectMeta:            metav1.ObjectMeta{Name: browserClientName},
 		Secret:                base64.RawURLEncoding.EncodeToString(randomBits(256)),
 		RespondWithChallenges: false,
 		RedirectURIs:          []string{oauthdiscovery.OpenShiftOAuthTokenDisplayURL(masterPublicURL)},
 		GrantMethod:           oauthv1.GrantHandlerAuto,
+
+		AccessTokenInactivityTimeoutSeconds: clientOverrides[browserClientName].AccessTokenInactivityTimeoutSeconds,
 	}
-	if err := ensureOAuthClient(ctx, c.oauthClientClient, browserClient); err != nil {
+	if err := ensureOAuthClient(ctx, c.oauthClientClient, recorder, browserClient); err != nil {
 		return fmt.Errorf("unable to get %q bootstrapped OAuth client: %v", browserClient.Name, err)
 	}
 
 	cliClient := oauthv1.OAuthClient{
-		ObjectMeta:            metav1.ObjectMeta{Name: "openshift-challenging-client"},
+		ObjectMeta:            metav1.ObjectMeta{Name: cliClientName},
 		Secret:                "",
 		RespondWithChallenges: true,
 		RedirectURIs:          []string{oauthdiscovery.OpenShiftOAuthTokenImplicitURL(masterPublicURL)},
 		GrantMethod:           oauthv1.GrantHandlerAuto,
+
+		AccessTokenInactivityTimeoutSeconds: clientOverrides[cliClientName].AccessTokenInactivityTimeoutSeconds,
 	}
-	if err := ensureOAuthClient(ctx, c.oauthClientClient, cliClient); err != nil {
+	if err := ensureOAuthClient(ctx, c.oauthClientClient, recorder, cliClient); err != nil {
 		return fmt.Errorf("unable to get %q bootstrapped CLI OAuth client: %v", browserClient.Name, err)
 	}
 
@@ -154,7 +198,12 @@ func randomBits(bits int) []byte {
 	return b
 }
 
-func ensureOAuthClient(ctx context.Context, oauthClients oauthclient.OAuthClientInterface, client oauthv1.OAuthClient) error {
+// ensureOAuthClient creates the client or reconciles its fields, the clients are watched and
+// resynced periodically so that edits made outside of the operator are reverted
+func ensureOAuthClient(ctx context.Context, oauthClients oauthclient.OAuthClientInterface, recorder events.Recorder, client oauthv1.OAuthClient) error {
+	if timeout := client.AccessTokenInactivityTimeoutSeconds; timeout != nil {
+		client.Annotations = map[string]string{inactivityTimeoutAnnotation: strconv.Itoa(int(*timeout))}
+	}
 	_, err := oauthClients.Create(ctx, &client, metav1.CreateOptions{})
 	if err == nil || !apierrors.IsAlreadyExists(err) {
 		return err
@@ -179,12 +228,64 @@ func ensureOAuthClient(ctx context.Context, oauthClients oauthclient.OAuthClient
 		existingCopy.RedirectURIs = client.RedirectURIs
 		existingCopy.GrantMethod = client.GrantMethod
 		existingCopy.ScopeRestrictions = client.ScopeRestrictions
+		reconcileInactivityTimeout(existingCopy, client.AccessTokenInactivityTimeoutSeconds)
 
 		if equality.Semantic.DeepEqual(existing, existingCopy) {
 			return nil
 		}
 
-		_, err = oauthClients.Update(ctx, existingCopy, metav1.UpdateOptions{})
-		return err
+		if _, err := oauthClients.Update(ctx, existingCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		recorder.Eventf("OAuthClientReconciled", "The %s of the bootstrapped OAuth client %q differed from the expected ones and were reconciled",
+			strings.Join(changedOAuthClientFields(existing, existingCopy), ", "), client.Name)
+		return nil
 	})
 }
+
+// reconcileInactivityTimeout sets the inactivity timeout of the overrides on the client. Without
+// an override, only the timeout the operator set before is cleared, a timeout set on the client
+// by someone else is left alone.
+func reconcileInactivityTimeout(client *oauthv1.OAuthClient, timeout *int32) {
+	if timeout != nil {
+		client.AccessTokenInactivityTimeoutSeconds = timeout
+		metav1.SetMetaDataAnnotation(&client.ObjectMeta, inactivityTimeoutAnnotation, strconv.Itoa(int(*timeout)))
+		return
+	}
+
+	operatorSet, ok := client.Annotations[inactivityTimeoutAnnotation]
+	if !ok {
+		return
+	}
+	if current := client.AccessTokenInactivityTimeoutSeconds; current != nil && strconv.Itoa(int(*current)) == operatorSet {
+		client.AccessTokenInactivityTimeoutSeconds = nil
+	}
+	delete(client.Annotations, inactivityTimeoutAnnotation)
+}
+
+// changedOAuthClientFields names the reconciled fields for the event, the secret is never shown
+func changedOAuthClientFields(existing, reconciled *oauthv1.OAuthClient) []string {
+	fields := []string{}
+	if existing.Secret != reconciled.Secret {
+		fields = append(fields, "secret")
+	}
+	if existing.RespondWithChallenges != reconciled.RespondWithChallenges {
+		fields = append(fields, "respondWithChallenges")
+	}
+	if !equality.Semantic.DeepEqual(existing.RedirectURIs, reconciled.RedirectURIs) {
+		fields = append(fields, "redirectURIs")
+	}
+	if existing.GrantMethod != reconciled.GrantMethod {
+		fields = append(fields, "grantMethod")
+	}
+	if !equality.Semantic.DeepEqual(existing.ScopeRestrictions, reconciled.ScopeRestrictions) {
+		fields = append(fields, "scopeRestrictions")
+	}
+	if !equality.Semantic.DeepEqual(existing.AccessTokenInactivityTimeoutSeconds, reconciled.AccessTokenInactivityTimeoutSeconds) {
+		fields = append(fields, "accessTokenInactivityTimeoutSeconds")
+	}
+	if existing.Annotations[inactivityTimeoutAnnotation] != reconciled.Annotations[inactivityTimeoutAnnotation] {
+		fields = append(fields, inactivityTimeoutAnnotation+" annotation")
+	}
+	return fields
+}
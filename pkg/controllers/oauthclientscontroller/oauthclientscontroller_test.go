@@ -0,0 +1,176 @@
+package oauthclientscontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	oauthv1 "github.com/openshift/api/oauth/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	fakeoauthclient "github.com/openshift/client-go/oauth/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestEnsureBootstrappedOAuthClients(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fakeoauthclient.NewSimpleClientset()
+
+	c := &oauthsClientsController{
+		oauthClientClient: fakeClient.OauthV1().OAuthClients(),
+	}
+
+	require.NoError(t, c.ensureBootstrappedOAuthClients(ctx, events.NewInMemoryRecorder("test"), "https://oauth.example.com", nil))
+
+	// the clients must be retrievable right after they were created
+	browserClient, err := fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-browser-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, browserClient.Secret)
+	require.Equal(t, []string{"https://oauth.example.com/oauth/token/display"}, browserClient.RedirectURIs)
+	require.Equal(t, oauthv1.GrantHandlerAuto, browserClient.GrantMethod)
+
+	cliClient, err := fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-challenging-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, cliClient.Secret)
+	require.True(t, cliClient.RespondWithChallenges)
+	require.Equal(t, []string{"https://oauth.example.com/oauth/token/implicit"}, cliClient.RedirectURIs)
+
+	// a second pass over existing clients must not cause any updates
+	fakeClient.ClearActions()
+	require.NoError(t, c.ensureBootstrappedOAuthClients(ctx, events.NewInMemoryRecorder("test"), "https://oauth.example.com", nil))
+	for _, action := range fakeClient.Actions() {
+		require.NotEqual(t, "update", action.GetVerb(), "unexpected update of an already reconciled client: %v", action)
+	}
+
+	browserClientAfter, err := fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-browser-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, browserClient.Secret, browserClientAfter.Secret)
+}
+
+func TestEnsureBootstrappedOAuthClientsDrift(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fakeoauthclient.NewSimpleClientset()
+	c := &oauthsClientsController{
+		oauthClientClient: fakeClient.OauthV1().OAuthClients(),
+	}
+	require.NoError(t, c.ensureBootstrappedOAuthClients(ctx, events.NewInMemoryRecorder("test"), "https://oauth.example.com", nil))
+
+	// tamper with the browser client outside of the operator
+	browserClient, err := fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-browser-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	originalSecret := browserClient.Secret
+	browserClient.Secret = "short"
+	browserClient.RedirectURIs = []string{"https://attacker.example.com/callback"}
+	browserClient.GrantMethod = oauthv1.GrantHandlerPrompt
+	_, err = fakeClient.OauthV1().OAuthClients().Update(ctx, browserClient, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	recorder := events.NewInMemoryRecorder("test")
+	require.NoError(t, c.ensureBootstrappedOAuthClients(ctx, recorder, "https://oauth.example.com", nil))
+
+	browserClient, err = fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-browser-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://oauth.example.com/oauth/token/display"}, browserClient.RedirectURIs)
+	require.Equal(t, oauthv1.GrantHandlerAuto, browserClient.GrantMethod)
+	require.NotEqual(t, "short", browserClient.Secret)
+	require.NotEqual(t, originalSecret, browserClient.Secret, "a shortened secret is replaced with a newly generated one")
+
+	require.Len(t, recorder.Events(), 1)
+	event := recorder.Events()[0]
+	require.Equal(t, "OAuthClientReconciled", event.Reason)
+	require.Contains(t, event.Message, "secret, redirectURIs, grantMethod")
+	require.NotContains(t, event.Message, browserClient.Secret)
+}
+
+func TestEnsureBootstrappedOAuthClientsInactivityTimeout(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fakeoauthclient.NewSimpleClientset(&oauthv1.OAuthClient{
+		ObjectMeta:                          metav1.ObjectMeta{Name: "openshift-challenging-client"},
+		AccessTokenInactivityTimeoutSeconds: pointer.Int32(900),
+	})
+
+	c := &oauthsClientsController{
+		oauthClientClient: fakeClient.OauthV1().OAuthClients(),
+	}
+
+	overrides := map[string]oauthClientOverride{
+		"openshift-browser-client": {AccessTokenInactivityTimeoutSeconds: pointer.Int32(600)},
+	}
+	require.NoError(t, c.ensureBootstrappedOAuthClients(ctx, events.NewInMemoryRecorder("test"), "https://oauth.example.com", overrides))
+
+	browserClient, err := fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-browser-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, pointer.Int32(600), browserClient.AccessTokenInactivityTimeoutSeconds)
+
+	// a timeout set directly on a client without an override is kept
+	cliClient, err := fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-challenging-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, pointer.Int32(900), cliClient.AccessTokenInactivityTimeoutSeconds)
+
+	// the timeout of a removed override is cleared
+	require.NoError(t, c.ensureBootstrappedOAuthClients(ctx, events.NewInMemoryRecorder("test"), "https://oauth.example.com", nil))
+	browserClient, err = fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-browser-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Nil(t, browserClient.AccessTokenInactivityTimeoutSeconds)
+	require.NotContains(t, browserClient.Annotations, inactivityTimeoutAnnotation)
+	cliClient, err = fakeClient.OauthV1().OAuthClients().Get(ctx, "openshift-challenging-client", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, pointer.Int32(900), cliClient.AccessTokenInactivityTimeoutSeconds)
+}
+
+func TestGetOAuthClientOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     string
+		want          map[string]oauthClientOverride
+		expectedError bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "inactivity timeout",
+			overrides: `{"oauthClients": {"openshift-browser-client": {"accessTokenInactivityTimeoutSeconds": 600}}}`,
+			want: map[string]oauthClientOverride{
+				"openshift-browser-client": {AccessTokenInactivityTimeoutSeconds: pointer.Int32(600)},
+			},
+		},
+		{
+			name:      "tokens never time out",
+			overrides: `{"oauthClients": {"openshift-challenging-client": {"accessTokenInactivityTimeoutSeconds": 0}}}`,
+			want: map[string]oauthClientOverride{
+				"openshift-challenging-client": {AccessTokenInactivityTimeoutSeconds: pointer.Int32(0)},
+			},
+		},
+		{
+			name:          "timeout below the minimum",
+			overrides:     `{"oauthClients": {"openshift-browser-client": {"accessTokenInactivityTimeoutSeconds": 60}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown client",
+			overrides:     `{"oauthClients": {"console": {"accessTokenInactivityTimeoutSeconds": 600}}}`,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &operatorv1.OperatorSpec{}
+			if len(tt.overrides) > 0 {
+				spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tt.overrides)}
+			}
+
+			got, err := getOAuthClientOverrides(spec)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -48,7 +48,15 @@ func NewOAuthRouteCheckController(
 	ingressInformer := ingressInformerAllNamespaces.Informer()
 
 	endpointListFunc := func() ([]string, error) {
-		return listOAuthRoutes(ingressLister, routeLister)
+		operatorSpec, _, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			return nil, err
+		}
+		routeOverrides, err := common.GetRouteOverrides(operatorSpec)
+		if err != nil {
+			return nil, err
+		}
+		return listOAuthRoutes(ingressLister, routeLister, routeOverrides.HealthPath)
 	}
 
 	getTLSConfigFunc := func() (*tls.Config, error) {
@@ -65,7 +73,10 @@ func NewOAuthRouteCheckController(
 			routeInformer,
 			ingressInformer,
 		},
-		recorder)
+		recorder,
+		// e.g. the wildcard certificate of the router does not cover a multi-level host
+		endpointaccessible.WithCertHostMismatchReason("RouteCertHostMismatch"),
+	)
 }
 
 // NewOAuthServiceCheckController returns a controller that checks the health of authentication service.
@@ -121,7 +132,7 @@ func NewOAuthServiceEndpointsCheckController(
 
 func listOAuthServiceEndpoints(endpointsLister corev1listers.EndpointsLister) ([]string, error) {
 	var results []string
-	endpoints, err := endpointsLister.Endpoints("openshift-authentication").Get("oauth-openshift")
+	endpoints, err := endpointsLister.Endpoints(common.TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +151,7 @@ func listOAuthServiceEndpoints(endpointsLister corev1listers.EndpointsLister) ([
 
 func listOAuthServices(serviceLister corev1listers.ServiceLister) ([]string, error) {
 	var results []string
-	service, err := serviceLister.Services("openshift-authentication").Get("oauth-openshift")
+	service, err := serviceLister.Services(common.TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		return nil, err
 	}
@@ -154,22 +165,22 @@ func listOAuthServices(serviceLister corev1listers.ServiceLister) ([]string, err
 	return toHealthzURL(results), nil
 }
 
-func listOAuthRoutes(ingressConfigLister configv1lister.IngressLister, routeLister routev1listers.RouteLister) ([]string, error) {
+func listOAuthRoutes(ingressConfigLister configv1lister.IngressLister, routeLister routev1listers.RouteLister, healthPath string) ([]string, error) {
 	var results []string
 	ingressConfig, err := ingressConfigLister.Get("cluster")
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve ingress from cache: %w", err)
 	}
 
-	route, err := routeLister.Routes("openshift-authentication").Get("oauth-openshift")
+	route, err := routeLister.Routes(common.TargetNamespace).Get("oauth-openshift")
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve route from cache: %w", err)
 	}
 
 	// retrieve all the hostnames we want to be checking, don't care about any others
-	ingressStatus := common.GetComponentRouteStatus(ingressConfig, "openshift-authentication", "oauth-openshift")
+	ingressStatus := common.GetComponentRouteStatus(ingressConfig, common.TargetNamespace, "oauth-openshift")
 	if ingressStatus == nil || ingressStatus.CurrentHostnames == nil {
-		return nil, fmt.Errorf("ingress.config/cluster does not yet have status for the \"openshift-authentication/oauth-openshift\" route")
+		return nil, fmt.Errorf("ingress.config/cluster does not yet have status for the \"%s/oauth-openshift\" route", common.TargetNamespace)
 	}
 	wantedHostnames := sets.NewString()
 	for _, host := range ingressStatus.CurrentHostnames {
@@ -191,16 +202,17 @@ func listOAuthRoutes(ingressConfigLister configv1lister.IngressLister, routeList
 		}
 	}
 	if len(results) == 0 {
-		return nil, fmt.Errorf("route \"openshift-authentication/oauth-openshift\": status does not have a valid host address")
+		return nil, fmt.Errorf("route \"%s/oauth-openshift\": status does not have a valid host address", common.TargetNamespace)
 	}
 	if wantedHostnames.Len() > 0 {
 		return nil, fmt.Errorf(
-			"route \"openshift-authentication/oauth-openshift\": the following hostnames have not yet been admitted: %v",
+			"route \"%s/oauth-openshift\": the following hostnames have not yet been admitted: %v",
+			common.TargetNamespace,
 			wantedHostnames.List(), // use sorted list here to avoid infinite looping if condition gets created from this error
 		)
 	}
 
-	return toHealthzURL(results), nil
+	return toHealthURL(results, healthPath), nil
 }
 
 func getOAuthRouteTLSConfig(cmLister corev1listers.ConfigMapLister, secretLister corev1listers.SecretLister, ingressLister configv1lister.IngressLister, systemCABundle []byte) (*tls.Config, error) {
@@ -218,7 +230,7 @@ func getOAuthRouteTLSConfig(cmLister corev1listers.ConfigMapLister, secretLister
 		return nil, fmt.Errorf("ingress config domain cannot be empty")
 	}
 
-	certBytes, _, _, err := common.GetActiveRouterCertKeyBytes(secretLister, ingress, "openshift-authentication", "v4-0-config-system-router-certs", "v4-0-config-system-custom-router-certs")
+	certBytes, _, _, err := common.GetActiveRouterCertKeyBytes(secretLister, ingress, common.TargetNamespace, "v4-0-config-system-router-certs", "v4-0-config-system-custom-router-certs")
 	if err != nil {
 		return nil, err
 	}
@@ -256,14 +268,14 @@ func getOAuthRouteTLSConfig(cmLister corev1listers.ConfigMapLister, secretLister
 }
 
 func getOAuthEndpointTLSConfig(cmLister corev1listers.ConfigMapLister) (*tls.Config, error) {
-	serviceCACM, err := cmLister.ConfigMaps("openshift-authentication").Get("v4-0-config-system-service-ca")
+	serviceCACM, err := cmLister.ConfigMaps(common.TargetNamespace).Get("v4-0-config-system-service-ca")
 	if err != nil {
 		return nil, err
 	}
 
 	// find the domain that matches our route
 	if _, ok := serviceCACM.Data["service-ca.crt"]; !ok {
-		return nil, fmt.Errorf("\"service-ca.crt\" key of the \"openshift-authentication/v4-0-config-system-service-ca\" CM is empty")
+		return nil, fmt.Errorf("\"service-ca.crt\" key of the \"%s/v4-0-config-system-service-ca\" CM is empty", common.TargetNamespace)
 	}
 
 	rootCAs := x509.NewCertPool()
@@ -282,9 +294,13 @@ func getOAuthEndpointTLSConfig(cmLister corev1listers.ConfigMapLister) (*tls.Con
 }
 
 func toHealthzURL(urls []string) []string {
+	return toHealthURL(urls, "/healthz")
+}
+
+func toHealthURL(urls []string, healthPath string) []string {
 	var res []string
 	for _, url := range urls {
-		res = append(res, "https://"+url+"/healthz")
+		res = append(res, "https://"+url+healthPath)
 	}
 	return res
 }
@@ -14,6 +14,8 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	configv1lister "github.com/openshift/client-go/config/listers/config/v1"
 	routev1listers "github.com/openshift/client-go/route/listers/route/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 )
 
 func Test_toHealthzURL(t *testing.T) {
@@ -42,6 +44,7 @@ func Test_listOAuthRoutes(t *testing.T) {
 		name          string
 		ingressConfig *configv1.Ingress
 		route         *routev1.Route
+		healthPath    string
 		want          []string
 		wantErr       bool
 	}{
@@ -95,6 +98,13 @@ func Test_listOAuthRoutes(t *testing.T) {
 			route:         authRoute("hostname.two", "hostname.tree", "hostname.one", "hostname.four"),
 			want:          []string{"https://hostname.two/healthz", "https://hostname.one/healthz"},
 		},
+		{
+			name:          "custom health path",
+			ingressConfig: authIngressConfig("hostname.one"),
+			route:         authRoute("hostname.one"),
+			healthPath:    "/oauth/healthz",
+			want:          []string{"https://hostname.one/oauth/healthz"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -107,7 +117,12 @@ func Test_listOAuthRoutes(t *testing.T) {
 				require.NoError(t, routes.Add(tt.route))
 			}
 
-			got, err := listOAuthRoutes(configv1lister.NewIngressLister(ingresses), routev1listers.NewRouteLister(routes))
+			healthPath := tt.healthPath
+			if len(healthPath) == 0 {
+				healthPath = common.DefaultRouteHealthPath
+			}
+
+			got, err := listOAuthRoutes(configv1lister.NewIngressLister(ingresses), routev1listers.NewRouteLister(routes), healthPath)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("listOAuthRoutes() error = %v, wantErr %v", err, tt.wantErr)
 				return
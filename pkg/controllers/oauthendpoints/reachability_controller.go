@@ -0,0 +1,98 @@
+package oauthendpoints
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	routeAvailableCondition   = "OAuthServerRouteEndpointAccessibleControllerAvailable"
+	serviceAvailableCondition = "OAuthServerServiceEndpointAccessibleControllerAvailable"
+
+	// reachabilityDisagreementGracePeriod covers the route and the service checks running
+	// at different times, each resyncs every one to two minutes
+	reachabilityDisagreementGracePeriod = 3 * time.Minute
+)
+
+// oauthReachabilityController compares the results of the route and the service checks,
+// the oauth-server reachable through only one of them points at the layer in between
+type oauthReachabilityController struct {
+	operatorClient v1helpers.OperatorClient
+	now            func() time.Time
+}
+
+// NewOAuthReachabilityController returns a controller that reports when the oauth-server is
+// reachable through its service but not through its route, or the other way around.
+func NewOAuthReachabilityController(
+	operatorClient v1helpers.OperatorClient,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &oauthReachabilityController{
+		operatorClient: operatorClient,
+		now:            time.Now,
+	}
+
+	return factory.New().
+		WithInformers(operatorClient.Informer()).
+		WithSync(c.sync).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("OAuthServerReachabilityController", recorder.WithComponentSuffix("oauth-server-reachability-controller"))
+}
+
+func (c *oauthReachabilityController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient,
+		v1helpers.UpdateConditionFn(reachabilityCondition(operatorStatus.Conditions, c.now())),
+	)
+	return err
+}
+
+func reachabilityCondition(conditions []operatorv1.OperatorCondition, now time.Time) operatorv1.OperatorCondition {
+	routeAvailable := v1helpers.FindOperatorCondition(conditions, routeAvailableCondition)
+	serviceAvailable := v1helpers.FindOperatorCondition(conditions, serviceAvailableCondition)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthServerReachabilityDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	// nothing to compare until both checks ran, and nothing to tell apart when both fail
+	if routeAvailable == nil || serviceAvailable == nil || routeAvailable.Status == serviceAvailable.Status {
+		return condition
+	}
+
+	unreachable, reachable := routeAvailable, serviceAvailable
+	reason, layer := "RouteUnreachable", "the router or the load balancer in front of it"
+	if serviceAvailable.Status != operatorv1.ConditionTrue {
+		unreachable, reachable = serviceAvailable, routeAvailable
+		reason, layer = "ServiceUnreachable", "the service network between the operator and the oauth-server"
+	}
+	if reachable.Status != operatorv1.ConditionTrue || now.Sub(unreachable.LastTransitionTime.Time) < reachabilityDisagreementGracePeriod {
+		return condition
+	}
+
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = reason
+	condition.Message = fmt.Sprintf("The oauth-server is reachable through its %s but not through its %s, check %s: %s",
+		checkedTarget(reachable.Type), checkedTarget(unreachable.Type), layer, unreachable.Message)
+	return condition
+}
+
+func checkedTarget(conditionType string) string {
+	if conditionType == routeAvailableCondition {
+		return "route"
+	}
+	return "service"
+}
@@ -0,0 +1,94 @@
+package oauthendpoints
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestReachabilityCondition(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	available := func(conditionType string, status operatorv1.ConditionStatus, since time.Duration) operatorv1.OperatorCondition {
+		return operatorv1.OperatorCondition{
+			Type:               conditionType,
+			Status:             status,
+			Message:            "connection refused",
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		conditions     []operatorv1.OperatorCondition
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "checks did not run yet",
+			conditions:     []operatorv1.OperatorCondition{available(routeAvailableCondition, operatorv1.ConditionFalse, time.Hour)},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "both reachable",
+			conditions: []operatorv1.OperatorCondition{
+				available(routeAvailableCondition, operatorv1.ConditionTrue, time.Hour),
+				available(serviceAvailableCondition, operatorv1.ConditionTrue, time.Hour),
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "both unreachable is left to the checks",
+			conditions: []operatorv1.OperatorCondition{
+				available(routeAvailableCondition, operatorv1.ConditionFalse, time.Hour),
+				available(serviceAvailableCondition, operatorv1.ConditionFalse, time.Hour),
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "route unreachable",
+			conditions: []operatorv1.OperatorCondition{
+				available(routeAvailableCondition, operatorv1.ConditionFalse, 5*time.Minute),
+				available(serviceAvailableCondition, operatorv1.ConditionTrue, time.Hour),
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "RouteUnreachable",
+		},
+		{
+			name: "service unreachable",
+			conditions: []operatorv1.OperatorCondition{
+				available(routeAvailableCondition, operatorv1.ConditionTrue, time.Hour),
+				available(serviceAvailableCondition, operatorv1.ConditionFalse, 5*time.Minute),
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ServiceUnreachable",
+		},
+		{
+			name: "recent disagreement is given time",
+			conditions: []operatorv1.OperatorCondition{
+				available(routeAvailableCondition, operatorv1.ConditionFalse, time.Minute),
+				available(serviceAvailableCondition, operatorv1.ConditionTrue, time.Hour),
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := reachabilityCondition(tt.conditions, now)
+			require.Equal(t, "OAuthServerReachabilityDegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+			if tt.expectedStatus == operatorv1.ConditionTrue {
+				require.Contains(t, condition.Message, "connection refused")
+			}
+		})
+	}
+}
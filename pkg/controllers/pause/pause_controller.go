@@ -0,0 +1,58 @@
+package pause
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+// reconciliationPausedController reports whether the reconciliation of the
+// operands was paused by the pause annotation on the operator config
+type reconciliationPausedController struct {
+	operatorClient v1helpers.OperatorClient
+}
+
+func NewReconciliationPausedController(
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &reconciliationPausedController{
+		operatorClient: operatorClient,
+	}
+
+	return factory.New().
+		WithInformers(operatorClient.Informer()).
+		WithSync(c.sync).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("ReconciliationPausedController", eventRecorder.WithComponentSuffix("reconciliation-paused-controller"))
+}
+
+func (c *reconciliationPausedController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	paused, err := common.IsOperatorReconciliationPaused(c.operatorClient)
+	if err != nil {
+		return err
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   "ReconciliationPaused",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	if paused {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "PauseAnnotationSet"
+		condition.Message = fmt.Sprintf("Changes to the operands are not reconciled until the %q annotation is removed from the operator config", common.ReconciliationPausedAnnotation)
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(condition))
+	return err
+}
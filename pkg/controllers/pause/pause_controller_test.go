@@ -0,0 +1,60 @@
+package pause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+func TestReconciliationPausedController(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		expectedStatus operatorv1.ConditionStatus
+	}{
+		{
+			name:           "not paused",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "not paused with unrelated annotations",
+			annotations:    map[string]string{"foo": "bar"},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "paused",
+			annotations:    map[string]string{common.ReconciliationPausedAnnotation: ""},
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+				&metav1.ObjectMeta{Name: "cluster", Annotations: tt.annotations},
+				&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+
+			c := &reconciliationPausedController{operatorClient: operatorClient}
+			require.NoError(t, c.sync(context.Background(), factory.NewSyncContext("testctx", events.NewInMemoryRecorder("test-recorder"))))
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			require.NoError(t, err)
+
+			condition := v1helpers.FindOperatorCondition(status.Conditions, "ReconciliationPaused")
+			require.NotNil(t, condition)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/operator/assets"
+)
+
+const (
+	// loginBannerSecretName holds the branded login and provider selection templates
+	// with the login banner, it is mounted optionally into the oauth-server pods
+	loginBannerSecretName = "v4-0-config-system-login-banner-template"
+	loginBannerMountPath  = "/var/config/system/secrets/" + loginBannerSecretName + "/"
+
+	// brandingTemplatesMountPath is where the default branded templates are mounted,
+	// the banner can only be added to these, never to the ones the user provides
+	brandingTemplatesMountPath = "/var/config/system/secrets/v4-0-config-system-ocp-branding-template/"
+
+	maxLoginBannerLength = 1024
+
+	// loginBannerMarker is the element of the branded templates the banner is put in front of
+	loginBannerMarker = `<div class="pf-c-login__main-body">`
+)
+
+// getLoginBanner returns the oauthServerLoginBanner.text key of the unsupportedConfigOverrides,
+// the text shown on top of the login and provider selection pages
+func getLoginBanner(spec *operatorv1.OperatorSpec) (string, error) {
+	unsupportedConfig := map[string]interface{}{}
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
+		return "", err
+	}
+
+	value, found, err := unstructured.NestedFieldNoCopy(unsupportedConfig, "oauthServerLoginBanner", "text")
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", nil
+	}
+
+	banner, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("unsupported oauthServerLoginBanner.text %v, must be a string", value)
+	}
+	banner = strings.TrimSpace(banner)
+	if length := utf8.RuneCountInString(banner); length > maxLoginBannerLength {
+		return "", fmt.Errorf("oauthServerLoginBanner.text must be at most %d characters long, got %d", maxLoginBannerLength, length)
+	}
+	return banner, nil
+}
+
+// loginBannerSecret renders the default branded templates with the banner
+func loginBannerSecret(banner string) (*corev1.Secret, error) {
+	branding := resourceread.ReadSecretV1OrDie(assets.MustAsset("oauth-openshift/branding-secret.yaml"))
+
+	bannerHTML := `<div class="pf-c-form__helper-text" role="note" style="white-space: pre-wrap; margin-bottom: var(--pf-global--spacer--lg);">` +
+		html.EscapeString(banner) + "</div>\n"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      loginBannerSecretName,
+			Namespace: common.TargetNamespace,
+			Labels: map[string]string{
+				"app": "oauth-openshift",
+			},
+		},
+		Data: map[string][]byte{},
+	}
+	for _, template := range []string{"login.html", "providers.html"} {
+		content := string(branding.Data[template])
+		if !strings.Contains(content, loginBannerMarker) {
+			return nil, fmt.Errorf("the branded %s template has no place for the login banner", template)
+		}
+		secret.Data[template] = []byte(strings.Replace(content, loginBannerMarker, bannerHTML+loginBannerMarker, 1))
+	}
+	return secret, nil
+}
+
+// handleLoginBanner points the login and provider selection templates of the observed config to
+// the ones with the banner. Custom templates of the user and the built-in community templates
+// are kept as they are and reported in the returned condition.
+func (c *payloadConfigController) handleLoginBanner(ctx context.Context, recorder events.Recorder, operatorSpec *operatorv1.OperatorSpec, observedConfigBytes []byte) ([]byte, []operatorv1.OperatorCondition) {
+	banner, err := getLoginBanner(operatorSpec)
+	if err != nil {
+		return nil, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "InvalidLoginBanner",
+				Message: err.Error(),
+			},
+		}
+	}
+
+	if len(banner) == 0 {
+		if _, err := c.secretLister.Secrets(common.TargetNamespace).Get(loginBannerSecretName); errors.IsNotFound(err) {
+			c.loginBannerRemovedAt = nil
+			return observedConfigBytes, nil
+		}
+		// the running oauth-servers read the templates until they are replaced
+		if unused, err := c.isLoginBannerUnused(); err != nil {
+			return nil, []operatorv1.OperatorCondition{
+				{
+					Type:    "OAuthConfigDegraded",
+					Status:  operatorv1.ConditionTrue,
+					Reason:  "GetLoginBannerUsageFailed",
+					Message: fmt.Sprintf("Unable to tell whether the login banner templates %q are still in use: %v", loginBannerSecretName, err),
+				},
+			}
+		} else if !unused {
+			return observedConfigBytes, nil
+		}
+		if err := c.secrets.Secrets(common.TargetNamespace).Delete(ctx, loginBannerSecretName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return nil, []operatorv1.OperatorCondition{
+				{
+					Type:    "OAuthConfigDegraded",
+					Status:  operatorv1.ConditionTrue,
+					Reason:  "DeleteLoginBannerFailed",
+					Message: fmt.Sprintf("Failed to delete the login banner templates %q: %v", loginBannerSecretName, err),
+				},
+			}
+		}
+		c.loginBannerRemovedAt = nil
+		return observedConfigBytes, nil
+	}
+	c.loginBannerRemovedAt = nil
+
+	observedConfig := map[string]interface{}{}
+	if err := json.Unmarshal(observedConfigBytes, &observedConfig); err != nil {
+		return nil, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "GetOAuthServerConfigFailed",
+				Message: fmt.Sprintf("Unable to decode the oauth-server configuration: %v", err),
+			},
+		}
+	}
+
+	loginTemplate, _, _ := unstructured.NestedString(observedConfig, "oauthConfig", "templates", "login")
+	if !strings.HasPrefix(loginTemplate, brandingTemplatesMountPath) {
+		return observedConfigBytes, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthLoginBannerIgnored",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "NoBrandedLoginTemplate",
+				Message: "The login banner is not shown, the login page is either customized in oauth.config.openshift.io/cluster or built into the oauth-server",
+			},
+		}
+	}
+
+	secret, err := loginBannerSecret(banner)
+	if err != nil {
+		return nil, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "RenderLoginBannerFailed",
+				Message: err.Error(),
+			},
+		}
+	}
+	if _, _, err := resourceapply.ApplySecret(ctx, c.secrets, recorder, secret); err != nil {
+		return nil, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "ApplyFailed",
+				Message: fmt.Sprintf("Failed to apply the login banner templates %q: %v", loginBannerSecretName, err),
+			},
+		}
+	}
+
+	if err := unstructured.SetNestedField(observedConfig, loginBannerMountPath+"login.html", "oauthConfig", "templates", "login"); err != nil {
+		return nil, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "MergeConfigFailed",
+				Message: fmt.Sprintf("Failed to set the login banner templates: %v", err),
+			},
+		}
+	}
+	providersTemplate, _, _ := unstructured.NestedString(observedConfig, "oauthConfig", "templates", "providerSelection")
+	if strings.HasPrefix(providersTemplate, brandingTemplatesMountPath) {
+		if err := unstructured.SetNestedField(observedConfig, loginBannerMountPath+"providers.html", "oauthConfig", "templates", "providerSelection"); err != nil {
+			return nil, []operatorv1.OperatorCondition{
+				{
+					Type:    "OAuthConfigDegraded",
+					Status:  operatorv1.ConditionTrue,
+					Reason:  "MergeConfigFailed",
+					Message: fmt.Sprintf("Failed to set the login banner templates: %v", err),
+				},
+			}
+		}
+	}
+
+	bannerConfigBytes, err := json.Marshal(observedConfig)
+	if err != nil {
+		return nil, []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "EncodeFailed",
+				Message: fmt.Sprintf("Failed to encode the oauth-server configuration: %v", err),
+			},
+		}
+	}
+	return bannerConfigBytes, nil
+}
+
+// isLoginBannerUnused tells whether the login banner templates can be deleted after the banner
+// was removed, i.e. the applied oauth-server config no longer points to them and a deployment
+// generation newer than the one at the removal rolled out completely. The removal is only known
+// to the running operator, after a restart the templates are kept until the next rollout.
+func (c *payloadConfigController) isLoginBannerUnused() (bool, error) {
+	deployment, err := c.deploymentLister.Deployments(common.TargetNamespace).Get("oauth-openshift")
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if c.loginBannerRemovedAt == nil {
+		removedAt := deployment.Generation
+		c.loginBannerRemovedAt = &removedAt
+		return false, nil
+	}
+
+	cliConfig, err := c.configMapLister.ConfigMaps(common.TargetNamespace).Get("v4-0-config-system-cliconfig")
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && strings.Contains(cliConfig.Data["v4-0-config-system-cliconfig"], loginBannerMountPath) {
+		return false, nil
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Generation > *c.loginBannerRemovedAt &&
+		deployment.Status.ObservedGeneration == deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas, nil
+}
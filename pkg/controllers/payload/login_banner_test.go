@@ -0,0 +1,198 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1lister "k8s.io/client-go/listers/apps/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestGetLoginBanner(t *testing.T) {
+	for overrides, expected := range map[string]string{
+		``: "",
+		`{"oauthServerLoginBanner": {"text": "  Authorized use only  "}}`: "Authorized use only",
+	} {
+		spec := &operatorv1.OperatorSpec{}
+		if len(overrides) > 0 {
+			spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(overrides)}
+		}
+		got, err := getLoginBanner(spec)
+		require.NoError(t, err)
+		require.Equal(t, expected, got, overrides)
+	}
+
+	for _, overrides := range []string{
+		`{"oauthServerLoginBanner": {"text": 42}}`,
+		`{"oauthServerLoginBanner": {"text": "` + strings.Repeat("x", maxLoginBannerLength+1) + `"}}`,
+	} {
+		_, err := getLoginBanner(&operatorv1.OperatorSpec{
+			UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(overrides)},
+		})
+		require.Error(t, err)
+	}
+}
+
+func TestHandleLoginBanner(t *testing.T) {
+	const (
+		brandedConfig = `{"oauthConfig":{"templates":{"login":"/var/config/system/secrets/v4-0-config-system-ocp-branding-template/login.html","providerSelection":"/var/config/system/secrets/v4-0-config-system-ocp-branding-template/providers.html"}}}`
+		customConfig  = `{"oauthConfig":{"templates":{"login":"/var/config/user/template/secret/v4-0-config-user-template-login/login.html"}}}`
+	)
+	bannerSpec := &operatorv1.OperatorSpec{
+		UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"oauthServerLoginBanner": {"text": "<b>Authorized use only</b>"}}`)},
+	}
+
+	tests := []struct {
+		name               string
+		spec               *operatorv1.OperatorSpec
+		observedConfig     string
+		expectedTemplates  map[string]interface{}
+		expectedConditions []string
+		expectSecret       bool
+	}{
+		{
+			name:           "no banner",
+			spec:           &operatorv1.OperatorSpec{},
+			observedConfig: brandedConfig,
+			expectedTemplates: map[string]interface{}{
+				"login":             brandingTemplatesMountPath + "login.html",
+				"providerSelection": brandingTemplatesMountPath + "providers.html",
+			},
+		},
+		{
+			name:           "banner on the branded templates",
+			spec:           bannerSpec,
+			observedConfig: brandedConfig,
+			expectedTemplates: map[string]interface{}{
+				"login":             loginBannerMountPath + "login.html",
+				"providerSelection": loginBannerMountPath + "providers.html",
+			},
+			expectSecret: true,
+		},
+		{
+			name:           "custom login template",
+			spec:           bannerSpec,
+			observedConfig: customConfig,
+			expectedTemplates: map[string]interface{}{
+				"login": "/var/config/user/template/secret/v4-0-config-user-template-login/login.html",
+			},
+			expectedConditions: []string{"OAuthLoginBannerIgnored"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			c := &payloadConfigController{
+				secretLister: corev1lister.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+				secrets:      kubeClient.CoreV1(),
+			}
+
+			configBytes, conditions := c.handleLoginBanner(context.Background(), events.NewInMemoryRecorder("test"), tt.spec, []byte(tt.observedConfig))
+			conditionTypes := []string{}
+			for _, condition := range conditions {
+				conditionTypes = append(conditionTypes, condition.Type)
+			}
+			require.ElementsMatch(t, tt.expectedConditions, conditionTypes)
+
+			config := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(configBytes, &config))
+			require.Equal(t, tt.expectedTemplates, config["oauthConfig"].(map[string]interface{})["templates"])
+
+			secret, err := kubeClient.CoreV1().Secrets("openshift-authentication").Get(context.Background(), loginBannerSecretName, metav1.GetOptions{})
+			if !tt.expectSecret {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, template := range []string{"login.html", "providers.html"} {
+				require.Contains(t, string(secret.Data[template]), "&lt;b&gt;Authorized use only&lt;/b&gt;")
+				require.NotContains(t, string(secret.Data[template]), "<b>Authorized use only</b>")
+			}
+		})
+	}
+}
+
+func TestHandleLoginBannerRemoval(t *testing.T) {
+	bannerSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: loginBannerSecretName}}
+	cliConfig := func(loginTemplate string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "v4-0-config-system-cliconfig"},
+			Data:       map[string]string{"v4-0-config-system-cliconfig": `{"oauthConfig":{"templates":{"login":"` + loginTemplate + `"}}}`},
+		}
+	}
+	deployment := func(generation, observedGeneration int64, updatedReplicas int32) *appsv1.Deployment {
+		replicas := int32(3)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift", Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: observedGeneration, Replicas: 3, UpdatedReplicas: updatedReplicas},
+		}
+	}
+
+	kubeClient := fake.NewSimpleClientset(bannerSecret)
+	secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	deploymentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, secretIndexer.Add(bannerSecret))
+	c := &payloadConfigController{
+		secretLister:     corev1lister.NewSecretLister(secretIndexer),
+		configMapLister:  corev1lister.NewConfigMapLister(configMapIndexer),
+		deploymentLister: appsv1lister.NewDeploymentLister(deploymentIndexer),
+		secrets:          kubeClient.CoreV1(),
+	}
+
+	for _, step := range []struct {
+		name          string
+		cliConfig     *corev1.ConfigMap
+		deployment    *appsv1.Deployment
+		expectDeleted bool
+	}{
+		{
+			name:       "banner just removed",
+			cliConfig:  cliConfig(loginBannerMountPath + "login.html"),
+			deployment: deployment(3, 3, 3),
+		},
+		{
+			name:       "config without the banner applied",
+			cliConfig:  cliConfig(brandingTemplatesMountPath + "login.html"),
+			deployment: deployment(3, 3, 3),
+		},
+		{
+			name:       "rolling out",
+			cliConfig:  cliConfig(brandingTemplatesMountPath + "login.html"),
+			deployment: deployment(4, 4, 1),
+		},
+		{
+			name:          "rolled out",
+			cliConfig:     cliConfig(brandingTemplatesMountPath + "login.html"),
+			deployment:    deployment(4, 4, 3),
+			expectDeleted: true,
+		},
+	} {
+		require.NoError(t, configMapIndexer.Update(step.cliConfig))
+		require.NoError(t, deploymentIndexer.Update(step.deployment))
+
+		_, conditions := c.handleLoginBanner(context.Background(), events.NewInMemoryRecorder("test"), &operatorv1.OperatorSpec{}, []byte(`{}`))
+		require.Empty(t, conditions, step.name)
+
+		_, err := kubeClient.CoreV1().Secrets("openshift-authentication").Get(context.Background(), loginBannerSecretName, metav1.GetOptions{})
+		if step.expectDeleted {
+			require.Error(t, err, step.name)
+		} else {
+			require.NoError(t, err, step.name)
+		}
+	}
+}
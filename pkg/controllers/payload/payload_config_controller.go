@@ -5,19 +5,26 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	appsv1lister "k8s.io/client-go/listers/apps/v1"
 	corev1lister "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 
@@ -36,6 +43,7 @@ import (
 
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
 )
 
 var (
@@ -48,20 +56,48 @@ func init() {
 	utilruntime.Must(osinv1.Install(scheme))
 }
 
+const (
+	// userSessionSecretName is the name of the secret in openshift-config that allows
+	// users to manage the session secrets of the oauth-server on their own
+	userSessionSecretName = "oauth-openshift-session"
+	userSessionSecretKey  = "sessionSecrets"
+
+	// sessionSecretSourceAnnotation tells whether the session secret was generated
+	// or copied from the user-managed secret
+	sessionSecretSourceAnnotation = "authentication.operator.openshift.io/session-secret-source"
+	userSessionSecretSource       = "openshift-config/" + userSessionSecretName
+)
+
+// cliConfigHashAnnotation holds the hash of the CLI config the operator applied last
+const cliConfigHashAnnotation = "authentication.operator.openshift.io/cliconfig-hash"
+
 // knownConditionNames lists all condition types used by this controller.
 // These conditions are operated and defaulted by this controller.
 // Any new condition used by this controller sync() loop should be listed here.
 var knownConditionNames = sets.NewString(
 	"OAuthConfigDegraded",
 	"OAuthSessionSecretDegraded",
+	"OAuthSessionSecretUserManaged",
+	"OAuthLoginBannerIgnored",
+	"OAuthConsoleAbsent",
 	"OAuthConfigRouteDegraded",
 	"OAuthConfigIngressDegraded",
 	"OAuthConfigServiceDegraded",
+	"OAuthConfigRoutePortDegraded",
+	"OAuthConfigRouteRouterDegraded",
 )
 
 type payloadConfigController struct {
-	serviceLister corev1lister.ServiceLister
-	routeLister   routev1lister.RouteLister
+	serviceLister    corev1lister.ServiceLister
+	configMapLister  corev1lister.ConfigMapLister
+	secretLister     corev1lister.SecretLister
+	userSecretLister corev1lister.SecretLister
+	routeLister      routev1lister.RouteLister
+	deploymentLister appsv1lister.DeploymentLister
+
+	// loginBannerRemovedAt is the generation of the oauth-server deployment when the login
+	// banner was removed, its templates are deleted once a newer generation rolled out
+	loginBannerRemovedAt *int64
 
 	auth           operatorv1client.AuthenticationsGetter
 	configMaps     corev1client.ConfigMapsGetter
@@ -69,15 +105,19 @@ type payloadConfigController struct {
 	operatorClient v1helpers.OperatorClient
 }
 
-func NewPayloadConfigController(kubeInformersForTargetNamespace informers.SharedInformerFactory, secrets corev1client.SecretsGetter, configMaps corev1client.ConfigMapsGetter,
+func NewPayloadConfigController(kubeInformersForTargetNamespace informers.SharedInformerFactory, userSecretInformer corev1informers.SecretInformer, secrets corev1client.SecretsGetter, configMaps corev1client.ConfigMapsGetter,
 	operatorClient v1helpers.OperatorClient, authentication operatorv1client.AuthenticationsGetter, routeInformer routeinformer.RouteInformer, recorder events.Recorder) factory.Controller {
 	c := &payloadConfigController{
-		serviceLister:  kubeInformersForTargetNamespace.Core().V1().Services().Lister(),
-		routeLister:    routeInformer.Lister(),
-		secrets:        secrets,
-		configMaps:     configMaps,
-		operatorClient: operatorClient,
-		auth:           authentication,
+		serviceLister:    kubeInformersForTargetNamespace.Core().V1().Services().Lister(),
+		configMapLister:  kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Lister(),
+		secretLister:     kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
+		userSecretLister: userSecretInformer.Lister(),
+		routeLister:      routeInformer.Lister(),
+		deploymentLister: kubeInformersForTargetNamespace.Apps().V1().Deployments().Lister(),
+		secrets:          secrets,
+		configMaps:       configMaps,
+		operatorClient:   operatorClient,
+		auth:             authentication,
 	}
 	return factory.New().WithInformers(
 		kubeInformersForTargetNamespace.Core().V1().Secrets().Informer(),
@@ -85,7 +125,13 @@ func NewPayloadConfigController(kubeInformersForTargetNamespace informers.Shared
 		kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Informer(),
 		routeInformer.Informer(),
 		operatorClient.Informer(),
-	).ResyncEvery(wait.Jitter(time.Minute, 1.0)).WithSync(c.sync).ToController("PayloadConfig", recorder.WithComponentSuffix("payload-config-controller"))
+	).WithFilteredEventsInformers(
+		common.NamesFilter(userSessionSecretName),
+		userSecretInformer.Informer(),
+	).WithBareInformers(
+		// the rollouts are only waited for before the login banner templates are deleted
+		kubeInformersForTargetNamespace.Apps().V1().Deployments().Informer(),
+	).ResyncEvery(wait.Jitter(time.Minute, 1.0)).WithSync(heartbeat.Track("PayloadConfig", c.sync)).ToController("PayloadConfig", recorder.WithComponentSuffix("payload-config-controller"))
 }
 
 func (c *payloadConfigController) getAuthConfig(ctx context.Context) (*operatorv1.Authentication, []operatorv1.OperatorCondition) {
@@ -103,9 +149,24 @@ func (c *payloadConfigController) getAuthConfig(ctx context.Context) (*operatorv
 	return operatorConfig, nil
 }
 
-func (c *payloadConfigController) getSessionSecret(ctx context.Context, recorder events.Recorder) []operatorv1.OperatorCondition {
-	secret, err := c.secrets.Secrets("openshift-authentication").Get(ctx, "v4-0-config-system-session", metav1.GetOptions{})
-	if err != nil || !isValidSessionSecret(secret) {
+func (c *payloadConfigController) getSessionSecret(ctx context.Context, recorder events.Recorder, operatorSpec *operatorv1.OperatorSpec) []operatorv1.OperatorCondition {
+	userSecret, err := c.userSecretLister.Secrets("openshift-config").Get(userSessionSecretName)
+	if err == nil {
+		return c.applyUserSessionSecret(ctx, recorder, userSecret)
+	} else if !errors.IsNotFound(err) {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthSessionSecretDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "GetFailed",
+				Message: fmt.Sprintf("Failed to get the user-managed session secret %s/%s: %v", "openshift-config", userSessionSecretName, err),
+			},
+		}
+	}
+
+	secret, err := c.secrets.Secrets(common.TargetNamespace).Get(ctx, "v4-0-config-system-session", metav1.GetOptions{})
+	// go back to the generated secrets when the user-managed ones are removed
+	if err != nil || !isValidSessionSecret(secret) || secret.Annotations[sessionSecretSourceAnnotation] == userSessionSecretSource {
 		klog.V(4).Infof("Failed to get session secret %q: %v (generating new random)", "v4-0-config-system-session", err)
 		secret, err = randomSessionSecret()
 		if err != nil {
@@ -119,6 +180,30 @@ func (c *payloadConfigController) getSessionSecret(ctx context.Context, recorder
 			}
 		}
 	}
+
+	rotation, err := getSessionSecretRotation(operatorSpec)
+	if err != nil {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthSessionSecretDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "InvalidRotationOverrides",
+				Message: err.Error(),
+			},
+		}
+	}
+	secret, err = rotateSessionSecret(secret, rotation, time.Now())
+	if err != nil {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthSessionSecretDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "RotateFailed",
+				Message: fmt.Sprintf("Failed to rotate session secret %q: %v", "v4-0-config-system-session", err),
+			},
+		}
+	}
+
 	if _, _, err := resourceapply.ApplySecret(ctx, c.secrets, recorder, secret); err != nil {
 		return []operatorv1.OperatorCondition{
 			{
@@ -132,9 +217,57 @@ func (c *payloadConfigController) getSessionSecret(ctx context.Context, recorder
 	return nil
 }
 
+// applyUserSessionSecret uses the session secrets managed by the user instead of the
+// generated ones, it never falls back to generating secrets if the user ones are invalid
+func (c *payloadConfigController) applyUserSessionSecret(ctx context.Context, recorder events.Recorder, userSecret *corev1.Secret) []operatorv1.OperatorCondition {
+	secret, err := userSessionSecret(userSecret)
+	if err != nil {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthSessionSecretDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "InvalidUserSessionSecret",
+				Message: fmt.Sprintf("The user-managed session secret %s/%s is invalid: %v", "openshift-config", userSessionSecretName, err),
+			},
+		}
+	}
+
+	if _, _, err := resourceapply.ApplySecret(ctx, c.secrets, recorder, secret); err != nil {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthSessionSecretDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "ApplyFailed",
+				Message: fmt.Sprintf("Failed to apply session secret %q: %v", "v4-0-config-system-session", err),
+			},
+		}
+	}
+
+	return []operatorv1.OperatorCondition{
+		{
+			Type:    "OAuthSessionSecretUserManaged",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "UserSessionSecretFound",
+			Message: fmt.Sprintf("The session secrets are managed by the user in %s/%s", "openshift-config", userSessionSecretName),
+		},
+	}
+}
+
 func (c *payloadConfigController) sync(ctx context.Context, syncContext factory.SyncContext) error {
+	if paused, err := common.IsOperatorReconciliationPaused(c.operatorClient); err != nil {
+		return err
+	} else if paused {
+		klog.V(4).Infof("reconciliation is paused, skipping sync")
+		return nil
+	}
+
+	operatorSpec, _, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
 	foundConditions := []operatorv1.OperatorCondition{}
-	foundConditions = append(foundConditions, c.getSessionSecret(ctx, syncContext.Recorder())...)
+	foundConditions = append(foundConditions, c.getSessionSecret(ctx, syncContext.Recorder(), operatorSpec)...)
 
 	route, routeConditions := common.GetOAuthServerRoute(c.routeLister, "OAuthConfigRoute")
 	foundConditions = append(foundConditions, routeConditions...)
@@ -145,13 +278,36 @@ func (c *payloadConfigController) sync(ctx context.Context, syncContext factory.
 	operatorConfig, operatorConfigConditions := c.getAuthConfig(ctx)
 	foundConditions = append(foundConditions, operatorConfigConditions...)
 
-	// we need route and service to be not nil
-	if len(foundConditions) == 0 {
+	// we need route and service to be not nil, informational conditions like the
+	// user-managed session secrets don't keep the config from being handled
+	prerequisitesFailed := false
+	for _, condition := range foundConditions {
+		if condition.Status == operatorv1.ConditionTrue && strings.HasSuffix(condition.Type, "Degraded") {
+			prerequisitesFailed = true
+		}
+	}
+
+	skippedConditions := sets.NewString()
+	if !prerequisitesFailed {
 		oauthConfigConditions := c.handleOAuthConfig(ctx, operatorConfig, route, service, syncContext.Recorder())
 		foundConditions = append(foundConditions, oauthConfigConditions...)
+	} else {
+		skippedConditions.Insert("OAuthConfigDegraded", "OAuthLoginBannerIgnored", "OAuthConsoleAbsent")
+	}
+
+	if route != nil && service != nil {
+		foundConditions = append(foundConditions, routeServicePortCondition(route, service))
+	} else {
+		skippedConditions.Insert("OAuthConfigRoutePortDegraded")
+	}
+
+	if route != nil {
+		foundConditions = append(foundConditions, routeRouterCondition(route))
+	} else {
+		skippedConditions.Insert("OAuthConfigRouteRouterDegraded")
 	}
 
-	return common.UpdateControllerConditions(ctx, c.operatorClient, knownConditionNames, foundConditions)
+	return common.UpdateStagedControllerConditions(ctx, c.operatorClient, knownConditionNames, skippedConditions, foundConditions)
 }
 
 func (c *payloadConfigController) handleOAuthConfig(ctx context.Context, operatorConfig *operatorv1.Authentication, route *routev1.Route, service *corev1.Service, recorder events.Recorder) []operatorv1.OperatorCondition {
@@ -192,7 +348,9 @@ func (c *payloadConfigController) handleOAuthConfig(ctx context.Context, operato
 				ServiceAccountMethod: osinv1.GrantHandlerPrompt,
 			},
 			SessionConfig: &osinv1.SessionConfig{
-				SessionSecretsFile:   "/var/config/system/secrets/v4-0-config-system-session/v4-0-config-system-session",
+				SessionSecretsFile: "/var/config/system/secrets/v4-0-config-system-session/v4-0-config-system-session",
+				// the lifetime of the session cookie of the login flows, it is independent of the
+				// token lifetimes and can be set in the "oauthServer" key of the unsupportedConfigOverrides
 				SessionMaxAgeSeconds: 5 * 60, // 5 minutes
 				SessionName:          "ssn",
 			},
@@ -223,6 +381,11 @@ func (c *payloadConfigController) handleOAuthConfig(ctx context.Context, operato
 		}
 	}
 
+	observedConfig, bannerConditions := c.handleLoginBanner(ctx, recorder, &operatorConfig.Spec.OperatorSpec, observedConfig)
+	if v1helpers.FindOperatorCondition(bannerConditions, "OAuthConfigDegraded") != nil {
+		return bannerConditions
+	}
+
 	unsupportedConfig, err := common.UnstructuredConfigFrom(operatorConfig.Spec.UnsupportedConfigOverrides.Raw, configobservation.OAuthServerConfigPrefix)
 	if err != nil {
 		return []operatorv1.OperatorCondition{
@@ -247,9 +410,54 @@ func (c *payloadConfigController) handleOAuthConfig(ctx context.Context, operato
 		}
 	}
 
+	// the merged config is decoded once for the checks guarding the oauth-server rollout
+	completeConfig := &osinv1.OsinServerConfig{}
+	if err := json.Unmarshal(completeConfigBytes, completeConfig); err != nil {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "DecodeConfigFailed",
+				Message: fmt.Sprintf("Failed to decode the merged config: %v", err),
+			},
+		}
+	}
+
+	for _, validation := range []struct {
+		reason   string
+		validate func(*osinv1.OsinServerConfig) error
+	}{
+		{reason: "InvalidServingLimits", validate: validateServingLimits},
+		{reason: "InvalidSessionConfig", validate: validateSessionConfig},
+		{reason: "InvalidCORSAllowedOrigins", validate: validateCORSAllowedOrigins},
+	} {
+		if err := validation.validate(completeConfig); err != nil {
+			return []operatorv1.OperatorCondition{
+				{
+					Type:    "OAuthConfigDegraded",
+					Status:  operatorv1.ConditionTrue,
+					Reason:  validation.reason,
+					Message: fmt.Sprintf("Invalid oauth-server configuration: %v", err),
+				},
+			}
+		}
+	}
+
 	expectedCLIConfig := getCliConfigMap(completeConfigBytes)
 
-	_, _, err = resourceapply.ApplyConfigMap(ctx, c.configMaps, recorder, expectedCLIConfig)
+	existingCLIConfig, err := c.configMapLister.ConfigMaps(expectedCLIConfig.Namespace).Get(expectedCLIConfig.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:    "OAuthConfigDegraded",
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "GetFailed",
+				Message: fmt.Sprintf("Unable to get CLI configuration %q: %v", expectedCLIConfig.Name, err),
+			},
+		}
+	}
+
+	_, modified, err := resourceapply.ApplyConfigMap(ctx, c.configMaps, recorder, expectedCLIConfig)
 	if err != nil {
 		return []operatorv1.OperatorCondition{
 			{
@@ -261,18 +469,120 @@ func (c *payloadConfigController) handleOAuthConfig(ctx context.Context, operato
 		}
 	}
 
+	if modified && existingCLIConfig != nil && isCLIConfigEditedManually(existingCLIConfig, expectedCLIConfig) {
+		recorder.Warningf("CLIConfigManualEditReverted", "Reverted a manual edit of the %s/%s config map, use the operator's unsupportedConfigOverrides to change the oauth-server configuration",
+			expectedCLIConfig.Namespace, expectedCLIConfig.Name)
+	}
+
+	return append(bannerConditions, consoleAbsentCondition(completeConfig))
+}
+
+// consoleAbsentCondition notes when the merged config has no console URL, either the console
+// capability is disabled or the console did not report its URL yet. The oauth-server then runs
+// without the console URL, the logins of the other clients are not affected.
+func consoleAbsentCondition(config *osinv1.OsinServerConfig) operatorv1.OperatorCondition {
+	if len(config.OAuthConfig.AssetPublicURL) > 0 {
+		return operatorv1.OperatorCondition{
+			Type:   "OAuthConsoleAbsent",
+			Status: operatorv1.ConditionFalse,
+			Reason: "ConsoleURLKnown",
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:    "OAuthConsoleAbsent",
+		Status:  operatorv1.ConditionTrue,
+		Reason:  "NoConsoleURL",
+		Message: "The web console is either disabled or did not report its URL yet, the oauth-server runs without the console URL",
+	}
+}
+
+// validateServingLimits checks the request and client limits of the merged config,
+// they can be tuned in the "oauthServer" key of the unsupportedConfigOverrides
+func validateServingLimits(config *osinv1.OsinServerConfig) error {
+	servingInfo := config.ServingInfo
+	// zero means no limit
+	if servingInfo.MaxRequestsInFlight < 0 {
+		return fmt.Errorf("servingInfo.maxRequestsInFlight must not be negative, got %d", servingInfo.MaxRequestsInFlight)
+	}
+	// -1 means no timeout
+	if servingInfo.RequestTimeoutSeconds < -1 {
+		return fmt.Errorf("servingInfo.requestTimeoutSeconds must be -1 or more, got %d", servingInfo.RequestTimeoutSeconds)
+	}
+
+	connectionOverrides := config.KubeClientConfig.ConnectionOverrides
+	if connectionOverrides.QPS < 0 || connectionOverrides.Burst < 0 {
+		return fmt.Errorf("kubeClientConfig.connectionOverrides qps and burst must not be negative, got %v and %d",
+			connectionOverrides.QPS, connectionOverrides.Burst)
+	}
+
 	return nil
 }
 
+// validateCORSAllowedOrigins checks the CORS origins of the merged config, they are observed
+// from the spec.additionalCORSAllowedOrigins of apiserver.config.openshift.io/cluster and the
+// oauth-server refuses to start when any of them is not a valid regular expression
+func validateCORSAllowedOrigins(config *osinv1.OsinServerConfig) error {
+	var errs []error
+	for _, origin := range config.CORSAllowedOrigins {
+		if len(origin) == 0 {
+			errs = append(errs, fmt.Errorf("corsAllowedOrigins must not contain an empty origin"))
+			continue
+		}
+		if _, err := regexp.Compile(origin); err != nil {
+			errs = append(errs, fmt.Errorf("corsAllowedOrigins %q is not a valid regular expression: %v", origin, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateSessionConfig checks the session cookie settings of the merged config, the config
+// map changes roll the oauth-server out so an invalid value would break every login
+func validateSessionConfig(config *osinv1.OsinServerConfig) error {
+	sessionConfig := config.OAuthConfig.SessionConfig
+	if sessionConfig == nil {
+		return fmt.Errorf("oauthConfig.sessionConfig must be set")
+	}
+	// zero makes it a browser session cookie
+	if sessionConfig.SessionMaxAgeSeconds < 0 {
+		return fmt.Errorf("oauthConfig.sessionConfig.sessionMaxAgeSeconds must not be negative, got %d", sessionConfig.SessionMaxAgeSeconds)
+	}
+	if len(sessionConfig.SessionName) == 0 {
+		return fmt.Errorf("oauthConfig.sessionConfig.sessionName must not be empty")
+	}
+
+	return nil
+}
+
+// isCLIConfigEditedManually returns true if the data of the existing CLI config is
+// no longer what the operator wrote and the operator is about to change them
+func isCLIConfigEditedManually(existing, expected *corev1.ConfigMap) bool {
+	appliedHash, ok := existing.Annotations[cliConfigHashAnnotation]
+	if !ok {
+		// written by an older version of the operator, we can't tell
+		return false
+	}
+
+	existingData := existing.Data["v4-0-config-system-cliconfig"]
+	return appliedHash != cliConfigHash(existingData) && existingData != expected.Data["v4-0-config-system-cliconfig"]
+}
+
+func cliConfigHash(cliConfig string) string {
+	hash := sha256.Sum256([]byte(cliConfig))
+	return hex.EncodeToString(hash[:])
+}
+
 func getCliConfigMap(completeConfigBytes []byte) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "v4-0-config-system-cliconfig",
-			Namespace: "openshift-authentication",
+			Namespace: common.TargetNamespace,
 			Labels: map[string]string{
 				"app": "oauth-openshift",
 			},
-			Annotations:     map[string]string{},
+			Annotations: map[string]string{
+				// allows to tell manual edits of the data apart from our own changes
+				cliConfigHashAnnotation: cliConfigHash(string(completeConfigBytes)),
+			},
 			OwnerReferences: nil, // TODO
 		},
 		Data: map[string]string{
@@ -282,7 +592,7 @@ func getCliConfigMap(completeConfigBytes []byte) *corev1.ConfigMap {
 }
 
 func (c *payloadConfigController) getExpectedSessionSecret(ctx context.Context) (*corev1.Secret, error) {
-	secret, err := c.secrets.Secrets("openshift-authentication").Get(ctx, "v4-0-config-system-session", metav1.GetOptions{})
+	secret, err := c.secrets.Secrets(common.TargetNamespace).Get(ctx, "v4-0-config-system-session", metav1.GetOptions{})
 	if err != nil || !isValidSessionSecret(secret) {
 		klog.V(4).Infof("failed to get secret %s: %v", "v4-0-config-system-session", err)
 		generatedSessionSecret, err := randomSessionSecret()
@@ -322,19 +632,61 @@ func isValidSessionSecret(secret *corev1.Secret) bool {
 	return true
 }
 
+// userSessionSecret builds the session secret for the oauth-server from the one managed by the user
+func userSessionSecret(userSecret *corev1.Secret) (*corev1.Secret, error) {
+	sessionSecretsJSON := userSecret.Data[userSessionSecretKey]
+	if len(sessionSecretsJSON) == 0 {
+		return nil, fmt.Errorf("the %q key is missing or empty", userSessionSecretKey)
+	}
+
+	var sessionSecrets osinv1.SessionSecrets
+	if err := json.Unmarshal(sessionSecretsJSON, &sessionSecrets); err != nil {
+		return nil, fmt.Errorf("the %q key does not contain SessionSecrets: %v", userSessionSecretKey, err)
+	}
+	if len(sessionSecrets.Secrets) == 0 {
+		return nil, fmt.Errorf("the %q key contains no secrets", userSessionSecretKey)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "v4-0-config-system-session",
+			Namespace: common.TargetNamespace,
+			Labels: map[string]string{
+				"app": "oauth-openshift",
+			},
+			Annotations: map[string]string{
+				sessionSecretSourceAnnotation: userSessionSecretSource,
+			},
+		},
+		Data: map[string][]byte{
+			"v4-0-config-system-session": sessionSecretsJSON,
+		},
+	}
+	if !isValidSessionSecret(secret) {
+		return nil, fmt.Errorf("the authentication secrets must be 64 bytes long and the encryption secrets must be 32 bytes long")
+	}
+
+	return secret, nil
+}
+
 func randomSessionSecret() (*corev1.Secret, error) {
 	skey, err := newSessionSecretsJSON()
 	if err != nil {
 		return nil, err
 	}
+	created := time.Now().UTC().Format(time.RFC3339)
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "v4-0-config-system-session",
-			Namespace: "openshift-authentication",
+			Namespace: common.TargetNamespace,
 			Labels: map[string]string{
 				"app": "oauth-openshift",
 			},
-			Annotations:     map[string]string{},
+			Annotations: map[string]string{
+				sessionSecretSourceAnnotation:         "generated",
+				sessionSigningKeyCreatedAnnotation:    created,
+				sessionEncryptionKeyCreatedAnnotation: created,
+			},
 			OwnerReferences: nil, // TODO
 		},
 		Data: map[string][]byte{
@@ -343,12 +695,13 @@ func randomSessionSecret() (*corev1.Secret, error) {
 	}, nil
 
 }
-func newSessionSecretsJSON() ([]byte, error) {
-	const (
-		sha256KeyLenBytes = sha256.BlockSize // max key size with HMAC SHA256
-		aes256KeyLenBytes = 32               // max key size with AES (AES-256)
-	)
 
+const (
+	sha256KeyLenBytes = sha256.BlockSize // max key size with HMAC SHA256
+	aes256KeyLenBytes = 32               // max key size with AES (AES-256)
+)
+
+func newSessionSecretsJSON() ([]byte, error) {
 	secrets := &osinv1.SessionSecrets{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "SessionSecrets",
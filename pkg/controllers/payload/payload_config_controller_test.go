@@ -0,0 +1,403 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	appsv1lister "k8s.io/client-go/listers/apps/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	osinv1 "github.com/openshift/api/osin/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	operatorv1client "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	routev1lister "github.com/openshift/client-go/route/listers/route/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestIsCLIConfigEditedManually(t *testing.T) {
+	const appliedConfig = `{"kind":"OsinServerConfig"}`
+	const editedConfig = `{"kind":"OsinServerConfig","edited":true}`
+	const newConfig = `{"kind":"OsinServerConfig","new":true}`
+
+	tests := []struct {
+		name     string
+		existing *corev1.ConfigMap
+		expected *corev1.ConfigMap
+		want     bool
+	}{
+		{
+			name:     "unchanged config",
+			existing: getCliConfigMap([]byte(appliedConfig)),
+			expected: getCliConfigMap([]byte(appliedConfig)),
+		},
+		{
+			name:     "config changed by the operator",
+			existing: getCliConfigMap([]byte(appliedConfig)),
+			expected: getCliConfigMap([]byte(newConfig)),
+		},
+		{
+			name:     "config edited manually",
+			existing: withData(getCliConfigMap([]byte(appliedConfig)), editedConfig),
+			expected: getCliConfigMap([]byte(appliedConfig)),
+			want:     true,
+		},
+		{
+			name:     "config edited manually to what the operator is about to apply",
+			existing: withData(getCliConfigMap([]byte(appliedConfig)), newConfig),
+			expected: getCliConfigMap([]byte(newConfig)),
+		},
+		{
+			name: "config written by an older operator",
+			existing: func() *corev1.ConfigMap {
+				cm := getCliConfigMap([]byte(editedConfig))
+				cm.Annotations = nil
+				return cm
+			}(),
+			expected: getCliConfigMap([]byte(appliedConfig)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCLIConfigEditedManually(tt.existing, tt.expected); got != tt.want {
+				t.Errorf("isCLIConfigEditedManually() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func withData(cm *corev1.ConfigMap, cliConfig string) *corev1.ConfigMap {
+	cm.Data["v4-0-config-system-cliconfig"] = cliConfig
+	return cm
+}
+
+func TestGetSessionSecret(t *testing.T) {
+	validSessionSecrets := func(t *testing.T) []byte {
+		sessionSecrets, err := json.Marshal(&osinv1.SessionSecrets{
+			Secrets: []osinv1.SessionSecret{{
+				Authentication: strings.Repeat("a", 64),
+				Encryption:     strings.Repeat("e", 32),
+			}},
+		})
+		require.NoError(t, err)
+		return sessionSecrets
+	}
+
+	tests := []struct {
+		name               string
+		userSecretData     map[string][]byte
+		existingSource     string
+		expectedConditions []string
+		expectUserSecrets  bool
+		expectRegenerated  bool
+	}{
+		{
+			name:              "no user-managed secret keeps the generated one",
+			existingSource:    "generated",
+			expectRegenerated: false,
+		},
+		{
+			name:               "valid user-managed secret is used",
+			userSecretData:     map[string][]byte{userSessionSecretKey: validSessionSecrets(t)},
+			existingSource:     "generated",
+			expectedConditions: []string{"OAuthSessionSecretUserManaged"},
+			expectUserSecrets:  true,
+		},
+		{
+			name:               "invalid user-managed secret degrades",
+			userSecretData:     map[string][]byte{userSessionSecretKey: []byte(`{"secrets":[{"authentication":"short","encryption":"short"}]}`)},
+			existingSource:     "generated",
+			expectedConditions: []string{"OAuthSessionSecretDegraded"},
+		},
+		{
+			name:               "user-managed secret without the key degrades",
+			userSecretData:     map[string][]byte{"foo": validSessionSecrets(t)},
+			existingSource:     "generated",
+			expectedConditions: []string{"OAuthSessionSecretDegraded"},
+		},
+		{
+			name:              "removed user-managed secret falls back to generated secrets",
+			existingSource:    userSessionSecretSource,
+			expectRegenerated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existingSecret, err := randomSessionSecret()
+			require.NoError(t, err)
+			existingSecret.Annotations[sessionSecretSourceAnnotation] = tt.existingSource
+			existingData := existingSecret.Data["v4-0-config-system-session"]
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tt.userSecretData != nil {
+				require.NoError(t, indexer.Add(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: userSessionSecretName},
+					Data:       tt.userSecretData,
+				}))
+			}
+
+			kubeClient := fake.NewSimpleClientset(existingSecret)
+			c := &payloadConfigController{
+				userSecretLister: corev1lister.NewSecretLister(indexer),
+				secrets:          kubeClient.CoreV1(),
+			}
+
+			conditions := c.getSessionSecret(context.Background(), events.NewInMemoryRecorder("test"), &operatorv1.OperatorSpec{})
+			conditionTypes := []string{}
+			for _, condition := range conditions {
+				conditionTypes = append(conditionTypes, condition.Type)
+			}
+			require.ElementsMatch(t, tt.expectedConditions, conditionTypes)
+
+			secret, err := kubeClient.CoreV1().Secrets("openshift-authentication").Get(context.Background(), "v4-0-config-system-session", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.True(t, isValidSessionSecret(secret))
+
+			switch {
+			case tt.expectUserSecrets:
+				require.Equal(t, tt.userSecretData[userSessionSecretKey], secret.Data["v4-0-config-system-session"])
+				require.Equal(t, userSessionSecretSource, secret.Annotations[sessionSecretSourceAnnotation])
+			case tt.expectRegenerated:
+				require.NotEqual(t, existingData, secret.Data["v4-0-config-system-session"])
+				require.Equal(t, "generated", secret.Annotations[sessionSecretSourceAnnotation])
+			default:
+				require.Equal(t, existingData, secret.Data["v4-0-config-system-session"])
+			}
+		})
+	}
+}
+
+// fakeAuthentications serves the operator config of the sync tests
+type fakeAuthentications struct {
+	operatorv1client.AuthenticationInterface
+	authentication *operatorv1.Authentication
+}
+
+func (f *fakeAuthentications) Authentications() operatorv1client.AuthenticationInterface {
+	return f
+}
+
+func (f *fakeAuthentications) Get(_ context.Context, _ string, _ metav1.GetOptions) (*operatorv1.Authentication, error) {
+	return f.authentication, nil
+}
+
+func TestSyncWithUserSessionSecret(t *testing.T) {
+	sessionSecrets, err := json.Marshal(&osinv1.SessionSecrets{
+		Secrets: []osinv1.SessionSecret{{
+			Authentication: strings.Repeat("a", 64),
+			Encryption:     strings.Repeat("e", 32),
+		}},
+	})
+	require.NoError(t, err)
+
+	userSecretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, userSecretIndexer.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: userSessionSecretName},
+		Data:       map[string][]byte{userSessionSecretKey: sessionSecrets},
+	}))
+
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, serviceIndexer.Add(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "https", Port: 443, TargetPort: intstr.FromInt(6443)},
+		}},
+	}))
+
+	routeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, routeIndexer.Add(&routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift"},
+		Spec: routev1.RouteSpec{
+			Host: "oauth-openshift.apps.example.com",
+			Port: &routev1.RoutePort{TargetPort: intstr.FromInt(6443)},
+		},
+	}))
+
+	kubeClient := fake.NewSimpleClientset()
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(&metav1.ObjectMeta{Name: "cluster"}, &operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	emptyIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &payloadConfigController{
+		serviceLister:    corev1lister.NewServiceLister(serviceIndexer),
+		configMapLister:  corev1lister.NewConfigMapLister(emptyIndexer),
+		secretLister:     corev1lister.NewSecretLister(emptyIndexer),
+		userSecretLister: corev1lister.NewSecretLister(userSecretIndexer),
+		routeLister:      routev1lister.NewRouteLister(routeIndexer),
+		deploymentLister: appsv1lister.NewDeploymentLister(emptyIndexer),
+		auth:             &fakeAuthentications{authentication: &operatorv1.Authentication{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}},
+		configMaps:       kubeClient.CoreV1(),
+		secrets:          kubeClient.CoreV1(),
+		operatorClient:   operatorClient,
+	}
+
+	require.NoError(t, c.sync(context.Background(), factory.NewSyncContext("testctx", events.NewInMemoryRecorder("test"))))
+
+	// the informational user-managed condition must not keep the config from being handled
+	_, err = kubeClient.CoreV1().ConfigMaps("openshift-authentication").Get(context.Background(), "v4-0-config-system-cliconfig", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, status, _, err := operatorClient.GetOperatorState()
+	require.NoError(t, err)
+	userManaged := v1helpers.FindOperatorCondition(status.Conditions, "OAuthSessionSecretUserManaged")
+	require.NotNil(t, userManaged)
+	require.Equal(t, operatorv1.ConditionTrue, userManaged.Status)
+	for _, conditionType := range []string{"OAuthConfigDegraded", "OAuthLoginBannerIgnored", "OAuthConsoleAbsent"} {
+		condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+		require.NotNil(t, condition, conditionType)
+		require.NotContains(t, condition.Message, "possibly stale", conditionType)
+	}
+	require.Equal(t, operatorv1.ConditionFalse, v1helpers.FindOperatorCondition(status.Conditions, "OAuthConfigDegraded").Status)
+}
+
+func TestValidateServingLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		expectedError bool
+	}{
+		{
+			name:   "defaults",
+			config: `{"servingInfo": {"maxRequestsInFlight": 1000, "requestTimeoutSeconds": 300}, "kubeClientConfig": {"connectionOverrides": {"qps": 400, "burst": 400}}}`,
+		},
+		{
+			name:   "no limits",
+			config: `{"servingInfo": {"maxRequestsInFlight": 0, "requestTimeoutSeconds": -1}}`,
+		},
+		{
+			name:          "negative max requests in flight",
+			config:        `{"servingInfo": {"maxRequestsInFlight": -1}}`,
+			expectedError: true,
+		},
+		{
+			name:          "negative request timeout",
+			config:        `{"servingInfo": {"requestTimeoutSeconds": -2}}`,
+			expectedError: true,
+		},
+		{
+			name:          "negative client burst",
+			config:        `{"kubeClientConfig": {"connectionOverrides": {"burst": -1}}}`,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateServingLimits(decodeConfig(t, tt.config))
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateSessionConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		expectedError bool
+	}{
+		{
+			name:   "defaults",
+			config: `{"oauthConfig": {"sessionConfig": {"sessionMaxAgeSeconds": 300, "sessionName": "ssn"}, "tokenConfig": {"accessTokenMaxAgeSeconds": 86400}}}`,
+		},
+		{
+			name:   "session shorter than the tokens",
+			config: `{"oauthConfig": {"sessionConfig": {"sessionMaxAgeSeconds": 60, "sessionName": "ssn"}, "tokenConfig": {"accessTokenMaxAgeSeconds": 86400}}}`,
+		},
+		{
+			name:   "browser session cookie",
+			config: `{"oauthConfig": {"sessionConfig": {"sessionMaxAgeSeconds": 0, "sessionName": "ssn"}}}`,
+		},
+		{
+			name:          "negative max age",
+			config:        `{"oauthConfig": {"sessionConfig": {"sessionMaxAgeSeconds": -1, "sessionName": "ssn"}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "no session name",
+			config:        `{"oauthConfig": {"sessionConfig": {"sessionMaxAgeSeconds": 300}}}`,
+			expectedError: true,
+		},
+		{
+			name:          "session config removed",
+			config:        `{"oauthConfig": {"sessionConfig": null}}`,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSessionConfig(decodeConfig(t, tt.config))
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateCORSAllowedOrigins(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		expectedError bool
+	}{
+		{
+			name:   "no origins",
+			config: `{}`,
+		},
+		{
+			name:   "cluster defaults and an additional origin",
+			config: `{"corsAllowedOrigins": ["//127\\.0\\.0\\.1(:|$)", "//localhost(:|$)", "//spa\\.example\\.com(:|$)"]}`,
+		},
+		{
+			name:          "malformed origin",
+			config:        `{"corsAllowedOrigins": ["//localhost(:|$)", "//spa.example.com(:|$"]}`,
+			expectedError: true,
+		},
+		{
+			name:          "empty origin",
+			config:        `{"corsAllowedOrigins": [""]}`,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCORSAllowedOrigins(decodeConfig(t, tt.config))
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConsoleAbsentCondition(t *testing.T) {
+	for config, expectedStatus := range map[string]operatorv1.ConditionStatus{
+		`{"oauthConfig": {"assetPublicURL": "https://console-openshift-console.apps.example.com"}}`: operatorv1.ConditionFalse,
+		`{"oauthConfig": {"assetPublicURL": ""}}`:                                                   operatorv1.ConditionTrue,
+		`{"oauthConfig": {}}`: operatorv1.ConditionTrue,
+	} {
+		condition := consoleAbsentCondition(decodeConfig(t, config))
+		require.Equal(t, "OAuthConsoleAbsent", condition.Type)
+		require.Equal(t, expectedStatus, condition.Status, config)
+	}
+}
+
+func decodeConfig(t *testing.T, config string) *osinv1.OsinServerConfig {
+	decoded := &osinv1.OsinServerConfig{}
+	require.NoError(t, json.Unmarshal([]byte(config), decoded))
+	return decoded
+}
@@ -0,0 +1,65 @@
+package payload
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+// defaultRouterName is the router name the default ingress controller admits routes with
+const defaultRouterName = "default"
+
+// routeRouterCondition degrades when the oauth-openshift route is admitted only by the routers
+// of other ingress controllers than the default one. Those serve the route with their own
+// certificates and policies, which the operator neither trusts nor probes. Routers admitting
+// the route on top of the default one are only listed in the message.
+func routeRouterCondition(route *routev1.Route) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthConfigRouteRouterDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+
+	admittedByDefault := false
+	unexpectedRouters := []string{}
+	for _, ingress := range route.Status.Ingress {
+		if !isAdmitted(ingress) {
+			continue
+		}
+		if ingress.RouterName == defaultRouterName {
+			admittedByDefault = true
+			continue
+		}
+		unexpectedRouters = append(unexpectedRouters, fmt.Sprintf("%q (host %s)", ingress.RouterName, ingress.Host))
+	}
+	if len(unexpectedRouters) == 0 {
+		return condition
+	}
+	sort.Strings(unexpectedRouters)
+
+	if admittedByDefault {
+		condition.Message = fmt.Sprintf("route %s/%s is also admitted by the routers %s",
+			route.Namespace, route.Name, strings.Join(unexpectedRouters, ", "))
+		return condition
+	}
+
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "UnexpectedRouter"
+	condition.Message = fmt.Sprintf("route %s/%s is not admitted by the %q router of the default ingress controller but by %s (check the route selectors of the ingress controllers)",
+		route.Namespace, route.Name, defaultRouterName, strings.Join(unexpectedRouters, ", "))
+	return condition
+}
+
+func isAdmitted(ingress routev1.RouteIngress) bool {
+	for _, condition := range ingress.Conditions {
+		if condition.Type == routev1.RouteAdmitted {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
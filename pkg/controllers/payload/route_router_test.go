@@ -0,0 +1,71 @@
+package payload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+func TestRouteRouterCondition(t *testing.T) {
+	ingress := func(routerName string, admitted corev1.ConditionStatus) routev1.RouteIngress {
+		return routev1.RouteIngress{
+			Host:       "oauth-openshift.apps.example.com",
+			RouterName: routerName,
+			Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: admitted}},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		ingresses       []routev1.RouteIngress
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "not admitted yet",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "admitted by the default router",
+			ingresses:      []routev1.RouteIngress{ingress("default", corev1.ConditionTrue)},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "rejected by another router",
+			ingresses:      []routev1.RouteIngress{ingress("default", corev1.ConditionTrue), ingress("internal", corev1.ConditionFalse)},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "admitted by another router as well",
+			ingresses:       []routev1.RouteIngress{ingress("default", corev1.ConditionTrue), ingress("internal", corev1.ConditionTrue)},
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedMessage: `also admitted by the routers "internal"`,
+		},
+		{
+			name:            "admitted by another router only",
+			ingresses:       []routev1.RouteIngress{ingress("default", corev1.ConditionFalse), ingress("internal", corev1.ConditionTrue)},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: `not admitted by the "default" router of the default ingress controller but by "internal"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift"},
+				Status:     routev1.RouteStatus{Ingress: tt.ingresses},
+			}
+
+			condition := routeRouterCondition(route)
+			require.Equal(t, "OAuthConfigRouteRouterDegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.True(t, strings.Contains(condition.Message, tt.expectedMessage), "expected %q in %q", tt.expectedMessage, condition.Message)
+		})
+	}
+}
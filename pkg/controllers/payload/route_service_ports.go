@@ -0,0 +1,64 @@
+package payload
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+// routeServicePortCondition degrades when the target port of the oauth-openshift route is none
+// of the ports of the oauth-openshift service. The router would forward the logins to a port
+// nothing serves and every login would fail with 503.
+func routeServicePortCondition(route *routev1.Route, service *corev1.Service) operatorv1.OperatorCondition {
+	condition := operatorv1.OperatorCondition{
+		Type:   "OAuthConfigRoutePortDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	// without a port the router forwards to all the ports of the service
+	if route.Spec.Port == nil {
+		return condition
+	}
+
+	targetPort := route.Spec.Port.TargetPort
+	servicePorts := make([]string, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		if routeTargetsServicePort(targetPort, port) {
+			return condition
+		}
+		podPort := serviceTargetPort(port)
+		servicePorts = append(servicePorts, fmt.Sprintf("%s (target port %s)", port.Name, podPort.String()))
+	}
+
+	condition.Status = operatorv1.ConditionTrue
+	condition.Reason = "RoutePortMismatch"
+	condition.Message = fmt.Sprintf("route %s/%s forwards to the port %s which is none of the ports of service %s/%s: %s",
+		route.Namespace, route.Name, targetPort.String(), service.Namespace, service.Name, strings.Join(servicePorts, ", "))
+	return condition
+}
+
+// routeTargetsServicePort tells whether the route target port selects the service port. Named
+// target ports are the names of the service ports, numbered ones are the ports of the pods
+// the service forwards to.
+func routeTargetsServicePort(targetPort intstr.IntOrString, port corev1.ServicePort) bool {
+	if targetPort.Type == intstr.String {
+		return targetPort.StrVal == port.Name
+	}
+
+	serviceTarget := serviceTargetPort(port)
+	// named pod ports can only be resolved against the pods, leave those to the router
+	return serviceTarget.Type == intstr.String || serviceTarget.IntVal == targetPort.IntVal
+}
+
+// serviceTargetPort returns the pod port of the service port, it defaults to the service port
+func serviceTargetPort(port corev1.ServicePort) intstr.IntOrString {
+	if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+		return intstr.FromInt(int(port.Port))
+	}
+	return port.TargetPort
+}
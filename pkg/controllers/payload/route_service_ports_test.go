@@ -0,0 +1,86 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+func TestRouteServicePortCondition(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "https", Port: 443, TargetPort: intstr.FromInt(6443)},
+		}},
+	}
+	route := func(port *routev1.RoutePort) *routev1.Route {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift"},
+			Spec:       routev1.RouteSpec{Port: port},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		route          *routev1.Route
+		service        *corev1.Service
+		expectedStatus operatorv1.ConditionStatus
+	}{
+		{
+			name:           "numbered target port",
+			route:          route(&routev1.RoutePort{TargetPort: intstr.FromInt(6443)}),
+			service:        service,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "named target port",
+			route:          route(&routev1.RoutePort{TargetPort: intstr.FromString("https")}),
+			service:        service,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "no target port",
+			route:          route(nil),
+			service:        service,
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:  "defaulted service target port",
+			route: route(&routev1.RoutePort{TargetPort: intstr.FromInt(443)}),
+			service: &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+				{Name: "https", Port: 443},
+			}}},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "numbered target port mismatch",
+			route:          route(&routev1.RoutePort{TargetPort: intstr.FromInt(443)}),
+			service:        service,
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:           "named target port mismatch",
+			route:          route(&routev1.RoutePort{TargetPort: intstr.FromString("http")}),
+			service:        service,
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := routeServicePortCondition(tt.route, tt.service)
+			require.Equal(t, "OAuthConfigRoutePortDegraded", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			if tt.expectedStatus == operatorv1.ConditionTrue {
+				require.Equal(t, "RoutePortMismatch", condition.Reason)
+				require.Contains(t, condition.Message, "https (target port 6443)")
+			}
+		})
+	}
+}
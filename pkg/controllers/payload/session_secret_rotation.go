@@ -0,0 +1,116 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	osinv1 "github.com/openshift/api/osin/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+const (
+	// the creation times of the session keys currently used to sign and encrypt
+	// new session cookies, they are tracked separately so that each can be rotated
+	// on its own cadence
+	sessionSigningKeyCreatedAnnotation    = "authentication.operator.openshift.io/session-signing-key-created"
+	sessionEncryptionKeyCreatedAnnotation = "authentication.operator.openshift.io/session-encryption-key-created"
+
+	// maxSessionSecrets is the number of session secrets kept after a rotation, the
+	// oauth-server uses the first one for new cookies and all of them to read cookies
+	// so the previous keys keep the cookies of the login flows in progress working
+	maxSessionSecrets = 3
+)
+
+// sessionSecretRotation are the rotation cadences of the generated session keys that can be
+// set in the "oauthServerSessionSecret" key of the operator's unsupportedConfigOverrides,
+// the keys are never rotated when unset
+type sessionSecretRotation struct {
+	SigningKeyMaxAge    metav1.Duration `json:"signingKeyMaxAge,omitempty"`
+	EncryptionKeyMaxAge metav1.Duration `json:"encryptionKeyMaxAge,omitempty"`
+}
+
+func getSessionSecretRotation(spec *operatorv1.OperatorSpec) (*sessionSecretRotation, error) {
+	overrides := struct {
+		OAuthServerSessionSecret sessionSecretRotation `json:"oauthServerSessionSecret"`
+	}{}
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode unsupportedConfigOverrides: %w", err)
+	}
+
+	rotation := &overrides.OAuthServerSessionSecret
+	if rotation.SigningKeyMaxAge.Duration < 0 {
+		return nil, fmt.Errorf("oauthServerSessionSecret.signingKeyMaxAge must not be negative, got %s", rotation.SigningKeyMaxAge.Duration)
+	}
+	if rotation.EncryptionKeyMaxAge.Duration < 0 {
+		return nil, fmt.Errorf("oauthServerSessionSecret.encryptionKeyMaxAge must not be negative, got %s", rotation.EncryptionKeyMaxAge.Duration)
+	}
+	return rotation, nil
+}
+
+// rotateSessionSecret returns the session secret with a new signing or encryption key, or
+// both, in front of the existing ones when they are older than their max age. The secret
+// is returned as it is when nothing is due, a changed secret rolls the oauth-server out.
+func rotateSessionSecret(secret *corev1.Secret, rotation *sessionSecretRotation, now time.Time) (*corev1.Secret, error) {
+	secret = secret.DeepCopy()
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+
+	var sessionSecrets osinv1.SessionSecrets
+	if err := json.Unmarshal(secret.Data["v4-0-config-system-session"], &sessionSecrets); err != nil {
+		return nil, fmt.Errorf("failed to decode the session secrets: %v", err)
+	}
+	if len(sessionSecrets.Secrets) == 0 {
+		return nil, fmt.Errorf("the session secret contains no secrets")
+	}
+
+	current := sessionSecrets.Secrets[0]
+	rotated := false
+	if keyDue(secret.Annotations, sessionSigningKeyCreatedAnnotation, rotation.SigningKeyMaxAge.Duration, now) {
+		current.Authentication = randomString(sha256KeyLenBytes)
+		secret.Annotations[sessionSigningKeyCreatedAnnotation] = now.UTC().Format(time.RFC3339)
+		rotated = true
+	}
+	if keyDue(secret.Annotations, sessionEncryptionKeyCreatedAnnotation, rotation.EncryptionKeyMaxAge.Duration, now) {
+		current.Encryption = randomString(aes256KeyLenBytes)
+		secret.Annotations[sessionEncryptionKeyCreatedAnnotation] = now.UTC().Format(time.RFC3339)
+		rotated = true
+	}
+	if !rotated {
+		return secret, nil
+	}
+
+	sessionSecrets.Secrets = append([]osinv1.SessionSecret{current}, sessionSecrets.Secrets...)
+	if len(sessionSecrets.Secrets) > maxSessionSecrets {
+		sessionSecrets.Secrets = sessionSecrets.Secrets[:maxSessionSecrets]
+	}
+	sessionSecretsBytes, err := json.Marshal(&sessionSecrets)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling the session secret: %v", err) // should never happen
+	}
+	secret.Data["v4-0-config-system-session"] = sessionSecretsBytes
+
+	return secret, nil
+}
+
+// keyDue tells whether the key whose creation time is recorded in the given annotation is
+// older than maxAge, keys of unknown age are given the current time and are not rotated.
+// The annotation is left alone while the rotation is not configured, any change to the
+// secret rolls the oauth-server out.
+func keyDue(annotations map[string]string, createdAnnotation string, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	created, err := time.Parse(time.RFC3339, annotations[createdAnnotation])
+	if err != nil {
+		annotations[createdAnnotation] = now.UTC().Format(time.RFC3339)
+		return false
+	}
+	return now.Sub(created) >= maxAge
+}
@@ -0,0 +1,108 @@
+package payload
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	osinv1 "github.com/openshift/api/osin/v1"
+)
+
+func TestGetSessionSecretRotation(t *testing.T) {
+	rotation, err := getSessionSecretRotation(&operatorv1.OperatorSpec{})
+	require.NoError(t, err)
+	require.Equal(t, &sessionSecretRotation{}, rotation)
+
+	rotation, err = getSessionSecretRotation(&operatorv1.OperatorSpec{
+		UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"oauthServerSessionSecret": {"signingKeyMaxAge": "720h", "encryptionKeyMaxAge": "2160h"}}`)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 720*time.Hour, rotation.SigningKeyMaxAge.Duration)
+	require.Equal(t, 2160*time.Hour, rotation.EncryptionKeyMaxAge.Duration)
+
+	_, err = getSessionSecretRotation(&operatorv1.OperatorSpec{
+		UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"oauthServerSessionSecret": {"signingKeyMaxAge": "-1h"}}`)},
+	})
+	require.Error(t, err)
+}
+
+func TestRotateSessionSecret(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotation := &sessionSecretRotation{
+		SigningKeyMaxAge:    metav1.Duration{Duration: 24 * time.Hour},
+		EncryptionKeyMaxAge: metav1.Duration{Duration: 72 * time.Hour},
+	}
+
+	secret, err := randomSessionSecret()
+	require.NoError(t, err)
+	secret.Annotations[sessionSigningKeyCreatedAnnotation] = now.Format(time.RFC3339)
+	secret.Annotations[sessionEncryptionKeyCreatedAnnotation] = now.Format(time.RFC3339)
+	initial := sessionSecretsOf(t, secret)
+
+	// nothing is due yet
+	notDue, err := rotateSessionSecret(secret, rotation, now.Add(23*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, secret.Data, notDue.Data)
+
+	// only the signing key is due
+	signingRotated, err := rotateSessionSecret(secret, rotation, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.NotEqual(t, secret.Data, signingRotated.Data, "a rotation must change the secret to roll the oauth-server out")
+	secrets := sessionSecretsOf(t, signingRotated)
+	require.Len(t, secrets, 2)
+	require.NotEqual(t, initial[0].Authentication, secrets[0].Authentication)
+	require.Equal(t, initial[0].Encryption, secrets[0].Encryption)
+	require.Equal(t, initial[0], secrets[1], "the previous keys must be kept to read the existing cookies")
+	require.Equal(t, now.Add(24*time.Hour).Format(time.RFC3339), signingRotated.Annotations[sessionSigningKeyCreatedAnnotation])
+	require.Equal(t, now.Format(time.RFC3339), signingRotated.Annotations[sessionEncryptionKeyCreatedAnnotation])
+	require.True(t, isValidSessionSecret(signingRotated))
+
+	// the encryption key is due later, the signing key was just rotated
+	encryptionRotated, err := rotateSessionSecret(signingRotated, rotation, now.Add(72*time.Hour))
+	require.NoError(t, err)
+	secrets = sessionSecretsOf(t, encryptionRotated)
+	require.Len(t, secrets, 3)
+	require.NotEqual(t, initial[0].Encryption, secrets[0].Encryption)
+	require.NotEqual(t, initial[0].Authentication, secrets[0].Authentication)
+	require.Equal(t, now.Add(72*time.Hour).Format(time.RFC3339), encryptionRotated.Annotations[sessionEncryptionKeyCreatedAnnotation])
+	require.True(t, isValidSessionSecret(encryptionRotated))
+
+	// the oldest keys are dropped
+	bothRotated, err := rotateSessionSecret(encryptionRotated, rotation, now.Add(200*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, sessionSecretsOf(t, bothRotated), maxSessionSecrets)
+	require.NotContains(t, sessionSecretsOf(t, bothRotated), initial[0])
+}
+
+func TestRotateSessionSecretWithoutCreationTimes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret, err := randomSessionSecret()
+	require.NoError(t, err)
+	delete(secret.Annotations, sessionSigningKeyCreatedAnnotation)
+	delete(secret.Annotations, sessionEncryptionKeyCreatedAnnotation)
+
+	// the secrets generated before the rotation existed are not rotated right away
+	rotated, err := rotateSessionSecret(secret, &sessionSecretRotation{SigningKeyMaxAge: metav1.Duration{Duration: time.Hour}}, now)
+	require.NoError(t, err)
+	require.Equal(t, secret.Data, rotated.Data)
+	require.Equal(t, now.Format(time.RFC3339), rotated.Annotations[sessionSigningKeyCreatedAnnotation])
+	require.NotContains(t, rotated.Annotations, sessionEncryptionKeyCreatedAnnotation, "the keys that are not rotated must not be stamped")
+
+	// the secret is left as it is while the rotation is not configured
+	unchanged, err := rotateSessionSecret(secret, &sessionSecretRotation{}, now)
+	require.NoError(t, err)
+	require.Equal(t, secret, unchanged)
+}
+
+func sessionSecretsOf(t *testing.T, secret *corev1.Secret) []osinv1.SessionSecret {
+	var sessionSecrets osinv1.SessionSecrets
+	require.NoError(t, json.Unmarshal(secret.Data["v4-0-config-system-session"], &sessionSecrets))
+	return sessionSecrets.Secrets
+}
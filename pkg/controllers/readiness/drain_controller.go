@@ -0,0 +1,113 @@
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	discoveryv1lister "k8s.io/client-go/listers/discovery/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+const (
+	drainControllerName = "OAuthServerDrainController"
+
+	// the oauth-server pods get 40s to terminate, pods that keep serving for
+	// much longer than that are not going to drain by themselves
+	drainMaxAge = 5 * time.Minute
+)
+
+// oauthServerDrainController reports Progressing while the oauth-server pods that
+// are being replaced are still serving behind the service so that the rollout is
+// not considered safe until they have stopped. Unlike the Endpoints, where the
+// terminating pods disappear right away, the EndpointSlices keep them along with
+// their serving condition.
+type oauthServerDrainController struct {
+	endpointSliceLister discoveryv1lister.EndpointSliceLister
+	operatorClient      v1helpers.OperatorClient
+}
+
+func NewOAuthServerDrainController(kubeInformersForTargetNamespace informers.SharedInformerFactory, operatorClient v1helpers.OperatorClient, recorder events.Recorder) factory.Controller {
+	c := &oauthServerDrainController{
+		endpointSliceLister: kubeInformersForTargetNamespace.Discovery().V1().EndpointSlices().Lister(),
+		operatorClient:      operatorClient,
+	}
+
+	return factory.New().WithInformers(
+		kubeInformersForTargetNamespace.Discovery().V1().EndpointSlices().Informer(),
+	).
+		WithSync(c.sync).
+		WithSyncDegradedOnError(operatorClient).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController(drainControllerName, recorder.WithComponentSuffix("oauth-server-drain-controller"))
+}
+
+func (c *oauthServerDrainController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	endpointSlices, err := c.endpointSliceLister.EndpointSlices(common.TargetNamespace).List(labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: "oauth-openshift"}))
+	if err != nil {
+		return err
+	}
+
+	progressingCondition := operatorv1.OperatorCondition{
+		Type:   common.ControllerProgressingConditionName(drainControllerName),
+		Status: operatorv1.ConditionFalse,
+	}
+	if err := checkOldReplicasDrained(endpointSlices); err != nil {
+		progressingErr, ok := err.(*common.ControllerProgressingError)
+		if !ok || progressingErr.IsDegraded(drainControllerName, operatorStatus) {
+			return err
+		}
+		progressingCondition = progressingErr.ToCondition(drainControllerName)
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(progressingCondition))
+	return err
+}
+
+// checkOldReplicasDrained fails when any of the terminating endpoints still receives
+// traffic, i.e. the readiness of its pod has not gone false yet
+func checkOldReplicasDrained(endpointSlices []*discoveryv1.EndpointSlice) error {
+	drainingPods := sets.NewString()
+	for _, slice := range endpointSlices {
+		for _, endpoint := range slice.Endpoints {
+			terminating := endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating
+			serving := endpoint.Conditions.Serving != nil && *endpoint.Conditions.Serving
+			if !terminating || !serving {
+				continue
+			}
+			name := strings.Join(endpoint.Addresses, ",")
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				name = endpoint.TargetRef.Name
+			}
+			drainingPods.Insert(name)
+		}
+	}
+
+	draining := drainingPods.List()
+	if len(draining) == 0 {
+		return nil
+	}
+
+	return common.NewControllerProgressingError(
+		"DrainingOldReplicas",
+		fmt.Errorf("draining old replicas: terminating pods are still serving: %s", strings.Join(draining, ", ")),
+		drainMaxAge,
+	)
+}
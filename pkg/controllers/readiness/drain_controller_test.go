@@ -0,0 +1,61 @@
+package readiness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+func TestCheckOldReplicasDrained(t *testing.T) {
+	endpoint := func(podName string, serving, terminating bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			Addresses:  []string{"10.128.0.10"},
+			Conditions: discoveryv1.EndpointConditions{Ready: &serving, Serving: &serving, Terminating: &terminating},
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: podName},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		endpoints           []discoveryv1.Endpoint
+		expectedProgressing bool
+	}{
+		{
+			name:      "no terminating pods",
+			endpoints: []discoveryv1.Endpoint{endpoint("oauth-a", true, false), endpoint("oauth-b", true, false)},
+		},
+		{
+			name:                "terminating pod still serving",
+			endpoints:           []discoveryv1.Endpoint{endpoint("oauth-a", true, true), endpoint("oauth-b", true, false)},
+			expectedProgressing: true,
+		},
+		{
+			name:      "terminating pod drained",
+			endpoints: []discoveryv1.Endpoint{endpoint("oauth-a", false, true), endpoint("oauth-b", true, false)},
+		},
+		{
+			name: "no conditions reported",
+			endpoints: []discoveryv1.Endpoint{{
+				Addresses: []string{"10.128.0.10"},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "oauth-a"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOldReplicasDrained([]*discoveryv1.EndpointSlice{{Endpoints: tt.endpoints}})
+			if !tt.expectedProgressing {
+				require.NoError(t, err)
+				return
+			}
+			progressingErr, ok := err.(*common.ControllerProgressingError)
+			require.True(t, ok, "expected a progressing error, got %v", err)
+			require.Equal(t, "DrainingOldReplicas", progressingErr.ToCondition(drainControllerName).Reason)
+		})
+	}
+}
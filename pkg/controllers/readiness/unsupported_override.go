@@ -1,17 +1,17 @@
 package readiness
 
 import (
-	"bytes"
-	"encoding/json"
-	configv1 "github.com/openshift/api/config/v1"
+	"fmt"
 	"strconv"
 
+	configv1 "github.com/openshift/api/config/v1"
+
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	kyaml "k8s.io/apimachinery/pkg/util/yaml"
-	"k8s.io/klog/v2"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 )
 
 // isUnsupportedUnsafeAuthentication returns true if
@@ -19,19 +19,7 @@ import (
 // to any parsable true value
 func isUnsupportedUnsafeAuthentication(spec *operatorv1.OperatorSpec) (bool, error) {
 	unsupportedConfig := map[string]interface{}{}
-	if spec.UnsupportedConfigOverrides.Raw == nil {
-		return false, nil
-	}
-
-	configJson, err := kyaml.ToJSON(spec.UnsupportedConfigOverrides.Raw)
-	if err != nil {
-		klog.Warning(err)
-		// maybe it's just json
-		configJson = spec.UnsupportedConfigOverrides.Raw
-	}
-
-	if err := json.NewDecoder(bytes.NewBuffer(configJson)).Decode(&unsupportedConfig); err != nil {
-		klog.V(4).Infof("decode of unsupported config failed with error: %v", err)
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
 		return false, err
 	}
 
@@ -69,3 +57,54 @@ func getExpectedMinimumNumberOfMasters(spec *operatorv1.OperatorSpec, topologyMo
 		return 3
 	}
 }
+
+// defaultWellKnownFailureThreshold is the number of consecutive failed well-known
+// checks after which an available well-known endpoint is reported unavailable
+const defaultWellKnownFailureThreshold = 3
+
+// getWellKnownFailureThreshold returns the wellKnownCheck.failureThreshold key, 1 reports
+// the well-known endpoint unavailable on the first failed check
+func getWellKnownFailureThreshold(spec *operatorv1.OperatorSpec) (int, error) {
+	unsupportedConfig := map[string]interface{}{}
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
+		return 0, err
+	}
+
+	value, found, err := unstructured.NestedFieldNoCopy(unsupportedConfig, "wellKnownCheck", "failureThreshold")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return defaultWellKnownFailureThreshold, nil
+	}
+
+	// JSON numbers are decoded as floats
+	threshold, ok := value.(float64)
+	if !ok || threshold != float64(int(threshold)) || threshold < 1 {
+		return 0, fmt.Errorf("unsupported wellKnownCheck.failureThreshold %v, must be a whole number of at least 1", value)
+	}
+	return int(threshold), nil
+}
+
+// isWellKnownRouteCheckEnabled returns the wellKnownCheck.probeRoute key, the oauth metadata
+// served through the oauth route are checked on top of those served by the kube-apiservers
+func isWellKnownRouteCheckEnabled(spec *operatorv1.OperatorSpec) (bool, error) {
+	unsupportedConfig := map[string]interface{}{}
+	if err := common.DecodeUnsupportedConfigOverrides(spec, &unsupportedConfig); err != nil {
+		return false, err
+	}
+
+	value, found, err := unstructured.NestedFieldNoCopy(unsupportedConfig, "wellKnownCheck", "probeRoute")
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+
+	probeRoute, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("unsupported wellKnownCheck.probeRoute %v, must be either true or false", value)
+	}
+	return probeRoute, nil
+}
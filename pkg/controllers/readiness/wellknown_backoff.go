@@ -0,0 +1,55 @@
+package readiness
+
+import (
+	"time"
+)
+
+const (
+	// probeBackoffThreshold is the number of consecutive failed probes of the kube-apiservers
+	// after which the probes get spaced out, the endpoint events of a kube-apiserver outage
+	// would otherwise trigger a probe of every kube-apiserver on each of them
+	probeBackoffThreshold = 5
+	probeBackoffBase      = time.Minute
+	probeBackoffMax       = 10 * time.Minute
+)
+
+// probeBreaker spaces out the well-known probes while they keep failing, the skipped probes
+// report the error of the last one so that the conditions are kept
+type probeBreaker struct {
+	failures  int
+	lastErr   error
+	nextProbe time.Time
+}
+
+// probe runs the check unless the breaker is open, it closes again once a check succeeds
+func (b *probeBreaker) probe(now time.Time, check func() error) (probed bool, err error) {
+	if b.failures >= probeBackoffThreshold && now.Before(b.nextProbe) {
+		return false, b.lastErr
+	}
+
+	if err := check(); err != nil {
+		b.failures++
+		b.lastErr = err
+		if b.failures >= probeBackoffThreshold {
+			b.nextProbe = now.Add(probeBackoff(b.failures))
+		}
+		return true, err
+	}
+
+	b.failures = 0
+	b.lastErr = nil
+	b.nextProbe = time.Time{}
+	return true, nil
+}
+
+// probeBackoff doubles the time between the probes for every failure past the threshold
+func probeBackoff(failures int) time.Duration {
+	backoff := probeBackoffBase
+	for i := probeBackoffThreshold; i < failures && backoff < probeBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > probeBackoffMax {
+		return probeBackoffMax
+	}
+	return backoff
+}
@@ -0,0 +1,61 @@
+package readiness
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	failures := 0
+	checkErr := fmt.Errorf("kube-apiserver unreachable")
+	check := func() error {
+		failures++
+		return checkErr
+	}
+
+	b := &probeBreaker{}
+	for i := 0; i < probeBackoffThreshold; i++ {
+		probed, err := b.probe(now, check)
+		require.True(t, probed)
+		require.Equal(t, checkErr, err)
+	}
+	require.Equal(t, probeBackoffThreshold, failures)
+
+	// open, the last error keeps being reported without probing
+	probed, err := b.probe(now.Add(30*time.Second), check)
+	require.False(t, probed)
+	require.Equal(t, checkErr, err)
+	require.Equal(t, probeBackoffThreshold, failures)
+
+	// half-open after the backoff, the failed probe doubles the backoff
+	now = now.Add(probeBackoffBase)
+	probed, _ = b.probe(now, check)
+	require.True(t, probed)
+	require.Equal(t, now.Add(2*probeBackoffBase), b.nextProbe)
+
+	probed, _ = b.probe(now.Add(probeBackoffBase), check)
+	require.False(t, probed)
+
+	// a successful probe closes the breaker
+	now = now.Add(2 * probeBackoffBase)
+	probed, err = b.probe(now, func() error { return nil })
+	require.True(t, probed)
+	require.NoError(t, err)
+
+	probed, err = b.probe(now, check)
+	require.True(t, probed)
+	require.Error(t, err)
+	require.Equal(t, 1, b.failures)
+}
+
+func TestProbeBackoff(t *testing.T) {
+	require.Equal(t, probeBackoffBase, probeBackoff(probeBackoffThreshold))
+	require.Equal(t, 2*probeBackoffBase, probeBackoff(probeBackoffThreshold+1))
+	require.Equal(t, 8*probeBackoffBase, probeBackoff(probeBackoffThreshold+3))
+	require.Equal(t, probeBackoffMax, probeBackoff(probeBackoffThreshold+4))
+	require.Equal(t, probeBackoffMax, probeBackoff(probeBackoffThreshold+100))
+}
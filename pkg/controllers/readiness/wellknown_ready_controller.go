@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
@@ -22,6 +23,7 @@ import (
 	routeinformer "github.com/openshift/client-go/route/informers/externalversions/route/v1"
 	routev1lister "github.com/openshift/client-go/route/listers/route/v1"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
 	"github.com/openshift/cluster-authentication-operator/pkg/transport"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -57,10 +59,23 @@ type wellKnownReadyController struct {
 	configMapLister      corev1lister.ConfigMapLister
 	routeLister          routev1lister.RouteLister
 	infrastructureLister configv1lister.InfrastructureLister
+
+	// consecutiveFailures counts the failed well-known checks since the last successful one,
+	// see recordFailure
+	consecutiveFailures int
+	// lastCountedFailure is when consecutiveFailures was last increased
+	lastCountedFailure time.Time
+	// probeBreaker backs off the probes of the kube-apiservers while they fail
+	probeBreaker probeBreaker
 }
 
 const controllerName = "WellKnownReadyController"
 
+// wellKnownFailureInterval is the least time between two failed checks that count as
+// consecutive failures, the syncs are triggered by the endpoint and config map events
+// that come in bursts while a kube-apiserver rolls out
+const wellKnownFailureInterval = 30 * time.Second
+
 func NewWellKnownReadyController(kubeInformers v1helpers.KubeInformersForNamespaces, configInformers configinformer.SharedInformerFactory, routeInformer routeinformer.RouteInformer,
 	operatorClient v1helpers.OperatorClient, recorder events.Recorder) factory.Controller {
 
@@ -85,7 +100,7 @@ func NewWellKnownReadyController(kubeInformers v1helpers.KubeInformersForNamespa
 		nsOpenshiftConfigManagedInformers.Core().V1().ConfigMaps().Informer(),
 		routeInformer.Informer(),
 	).
-		WithSync(c.sync).
+		WithSync(heartbeat.Track(controllerName, c.sync)).
 		WithSyncDegradedOnError(operatorClient).
 		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
 		ToController(controllerName, recorder.WithComponentSuffix("wellknown-ready-controller"))
@@ -115,7 +130,7 @@ func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext f
 		}
 	}()
 
-	route, err := c.routeLister.Routes("openshift-authentication").Get("oauth-openshift")
+	route, err := c.routeLister.Routes(common.TargetNamespace).Get("oauth-openshift")
 	if apierrors.IsNotFound(err) {
 		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{
 			Type:    "WellKnownAvailable",
@@ -128,7 +143,32 @@ func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext f
 		return err
 	}
 
-	if err := c.isWellknownEndpointsReady(operatorSpec, operatorStatus, authConfig, route, infraConfig); err != nil {
+	now := time.Now()
+	probed, err := c.probeBreaker.probe(now, func() error {
+		return c.isWellknownEndpointsReady(ctx, operatorSpec, operatorStatus, authConfig, route, infraConfig)
+	})
+	if !probed {
+		klog.V(4).Infof("skipping the well-known probe of the kube-apiservers after %d consecutive failures until %s", c.probeBreaker.failures, c.probeBreaker.nextProbe.Format(time.RFC3339))
+	}
+	if err != nil {
+		if probed {
+			c.recordFailure(now)
+		}
+		failureThreshold, thresholdErr := getWellKnownFailureThreshold(operatorSpec)
+		if thresholdErr != nil {
+			return thresholdErr
+		}
+		if isTransientWellKnownFailure(operatorStatus, c.consecutiveFailures, failureThreshold) {
+			// e.g. a kube-apiserver rolling out, keep the endpoint available for now
+			statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{
+				Type:    common.ControllerProgressingConditionName(controllerName),
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "WellKnownCheckFailing",
+				Message: fmt.Sprintf("The well-known check is failing, the endpoint is reported unavailable after %d consecutive failures: %v", failureThreshold, err),
+			}))
+			return nil
+		}
+
 		statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{
 			Type:    "WellKnownAvailable",
 			Status:  operatorv1.ConditionFalse,
@@ -147,6 +187,8 @@ func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext f
 		}
 	}
 
+	c.consecutiveFailures = 0
+	c.lastCountedFailure = time.Time{}
 	statusUpdates = append(statusUpdates, v1helpers.UpdateConditionFn(
 		operatorv1.OperatorCondition{
 			Type:   common.ControllerProgressingConditionName(controllerName),
@@ -161,7 +203,24 @@ func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext f
 	return nil
 }
 
-func (c *wellKnownReadyController) isWellknownEndpointsReady(spec *operatorv1.OperatorSpec, status *operatorv1.OperatorStatus, authConfig *configv1.Authentication, route *routev1.Route, infraConfig *configv1.Infrastructure) error {
+// recordFailure counts a failed probe of the well-known endpoints, the failures within
+// wellKnownFailureInterval of the last counted one are taken for the same failure
+func (c *wellKnownReadyController) recordFailure(now time.Time) {
+	if !c.lastCountedFailure.IsZero() && now.Sub(c.lastCountedFailure) < wellKnownFailureInterval {
+		return
+	}
+	c.consecutiveFailures++
+	c.lastCountedFailure = now
+}
+
+// isTransientWellKnownFailure tells whether a failed well-known check should leave an
+// available well-known endpoint available, only the steady state is debounced so that
+// the endpoint is not reported available before it first was
+func isTransientWellKnownFailure(status *operatorv1.OperatorStatus, consecutiveFailures, failureThreshold int) bool {
+	return consecutiveFailures < failureThreshold && v1helpers.IsOperatorConditionTrue(status.Conditions, "WellKnownAvailable")
+}
+
+func (c *wellKnownReadyController) isWellknownEndpointsReady(ctx context.Context, spec *operatorv1.OperatorSpec, status *operatorv1.OperatorStatus, authConfig *configv1.Authentication, route *routev1.Route, infraConfig *configv1.Infrastructure) error {
 	// don't perform this check when OAuthMetadata reference is set up
 	// leave those cases to KAS-o which handles these cases
 	// the operator manages the metadata if specifically requested and by default
@@ -193,6 +252,16 @@ func (c *wellKnownReadyController) isWellknownEndpointsReady(spec *operatorv1.Op
 		}
 	}
 
+	probeRoute, err := isWellKnownRouteCheckEnabled(spec)
+	if err != nil {
+		return err
+	}
+	if probeRoute {
+		if err := c.checkRouteWellknownEndpointReady(ctx, route); err != nil {
+			return err
+		}
+	}
+
 	// if we don't have the min number of masters, this is actually ok, however Clayton has draw a hardline on starting tests as soon as all operators are Available=true
 	// while ignoring progressing=false.  This means that even though no external observer will see a invalid .well-known information,
 	// the tests end up failing when their long lived connections are terminated.  Killing long lived connections is normal and
@@ -250,13 +319,50 @@ func (c *wellKnownReadyController) checkWellknownEndpointReady(apiIP string, rt
 		return fmt.Errorf("failed to unmarshal %s JSON: %v (check kube-apiserver logs if this error persists)", wellKnown, err)
 	}
 
-	if !reflect.DeepEqual(expectedMetadata, receivedValues) {
+	return compareOAuthMetadata(wellKnown, expectedMetadata, receivedValues)
+}
+
+// compareOAuthMetadata checks the metadata served by the kube-apiserver against the expected ones.
+// A different issuer is reported on its own as it means the kube-apiserver still serves the
+// discovery of a different oauth host, typically after the route host changed.
+func compareOAuthMetadata(wellKnown string, expectedMetadata, receivedValues map[string]interface{}) error {
+	expected, received := normalizeMetadataURLs(expectedMetadata), normalizeMetadataURLs(receivedValues)
+	if expectedIssuer, servedIssuer := expected["issuer"], received["issuer"]; !reflect.DeepEqual(expectedIssuer, servedIssuer) {
+		return common.NewControllerProgressingError("OAuthMetadataIssuerDiffer", fmt.Errorf("the %s endpoint serves the issuer %v but %v is expected (check kube-apiserver operator that instances roll out, which happens when the oauth route host changes)", wellKnown, servedIssuer, expectedIssuer), 5*time.Minute)
+	}
+
+	if !reflect.DeepEqual(expected, received) {
 		return common.NewControllerProgressingError("OAuthMetadataDiffer", fmt.Errorf("the %s endpoint returns different oauth metadata than is stored in openshift-config-managed/oauth-openshift ConfigMap (check kube-apiserver operator that instances roll out, which happens when oauth metadata changes)", wellKnown), 5*time.Minute)
 	}
 
 	return nil
 }
 
+// normalizeMetadataURLs returns a copy of the oauth metadata where the URL-valued fields
+// are stripped of the cosmetic differences, i.e. the case of the scheme and host and
+// trailing slashes. The rest of the path is kept intact as that's what clients use.
+func normalizeMetadataURLs(metadata map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		normalized[k] = v
+
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		u, err := url.Parse(s)
+		if err != nil || (!strings.EqualFold(u.Scheme, "https") && !strings.EqualFold(u.Scheme, "http")) || len(u.Host) == 0 {
+			continue
+		}
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+		normalized[k] = u.String()
+	}
+	return normalized
+}
+
 func wellKnownRoundtripErrorHint(err error) string {
 	switch {
 	case isConnectionRefusedError(err) || netutil.IsConnectionRefused(err):
@@ -0,0 +1,277 @@
+package readiness
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+func TestNormalizeMetadataURLs(t *testing.T) {
+	expected := map[string]interface{}{
+		"issuer":                 "https://oauth-openshift.apps.example.com",
+		"authorization_endpoint": "https://oauth-openshift.apps.example.com/oauth/authorize",
+		"token_endpoint":         "https://oauth-openshift.apps.example.com/oauth/token",
+		"response_types_supported": []interface{}{
+			"code",
+			"token",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		received      map[string]interface{}
+		expectedEqual bool
+	}{
+		{
+			name:          "identical",
+			received:      expected,
+			expectedEqual: true,
+		},
+		{
+			name: "trailing slashes",
+			received: map[string]interface{}{
+				"issuer":                   "https://oauth-openshift.apps.example.com/",
+				"authorization_endpoint":   "https://oauth-openshift.apps.example.com/oauth/authorize/",
+				"token_endpoint":           "https://oauth-openshift.apps.example.com/oauth/token",
+				"response_types_supported": []interface{}{"code", "token"},
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "scheme and host case",
+			received: map[string]interface{}{
+				"issuer":                   "HTTPS://OAuth-OpenShift.apps.example.com",
+				"authorization_endpoint":   "https://oauth-openshift.APPS.example.com/oauth/authorize",
+				"token_endpoint":           "Https://oauth-openshift.apps.example.com/oauth/token",
+				"response_types_supported": []interface{}{"code", "token"},
+			},
+			expectedEqual: true,
+		},
+		{
+			name: "different host",
+			received: map[string]interface{}{
+				"issuer":                   "https://oauth.apps.example.com",
+				"authorization_endpoint":   "https://oauth-openshift.apps.example.com/oauth/authorize",
+				"token_endpoint":           "https://oauth-openshift.apps.example.com/oauth/token",
+				"response_types_supported": []interface{}{"code", "token"},
+			},
+		},
+		{
+			name: "path case differs",
+			received: map[string]interface{}{
+				"issuer":                   "https://oauth-openshift.apps.example.com",
+				"authorization_endpoint":   "https://oauth-openshift.apps.example.com/OAuth/Authorize",
+				"token_endpoint":           "https://oauth-openshift.apps.example.com/oauth/token",
+				"response_types_supported": []interface{}{"code", "token"},
+			},
+		},
+		{
+			name: "different path",
+			received: map[string]interface{}{
+				"issuer":                   "https://oauth-openshift.apps.example.com",
+				"authorization_endpoint":   "https://oauth-openshift.apps.example.com/oauth/authorize",
+				"token_endpoint":           "https://oauth-openshift.apps.example.com/oauth/token/extra",
+				"response_types_supported": []interface{}{"code", "token"},
+			},
+		},
+		{
+			name: "non-URL values are compared as they are",
+			received: map[string]interface{}{
+				"issuer":                   "https://oauth-openshift.apps.example.com",
+				"authorization_endpoint":   "https://oauth-openshift.apps.example.com/oauth/authorize",
+				"token_endpoint":           "https://oauth-openshift.apps.example.com/oauth/token",
+				"response_types_supported": []interface{}{"Code", "token"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if equal := reflect.DeepEqual(normalizeMetadataURLs(expected), normalizeMetadataURLs(tt.received)); equal != tt.expectedEqual {
+				t.Errorf("expected the metadata to be equal: %v, got %v", tt.expectedEqual, equal)
+			}
+		})
+	}
+}
+
+func TestCompareOAuthMetadata(t *testing.T) {
+	expected := map[string]interface{}{
+		"issuer":         "https://oauth-openshift.apps.example.com",
+		"token_endpoint": "https://oauth-openshift.apps.example.com/oauth/token",
+	}
+
+	tests := []struct {
+		name           string
+		received       map[string]interface{}
+		expectedReason string
+	}{
+		{
+			name:     "equal",
+			received: map[string]interface{}{"issuer": "https://oauth-openshift.apps.example.com/", "token_endpoint": "https://oauth-openshift.apps.example.com/oauth/token"},
+		},
+		{
+			name:           "different issuer",
+			received:       map[string]interface{}{"issuer": "https://oauth.old.example.com", "token_endpoint": "https://oauth-openshift.apps.example.com/oauth/token"},
+			expectedReason: "OAuthMetadataIssuerDiffer",
+		},
+		{
+			name:           "missing issuer",
+			received:       map[string]interface{}{"token_endpoint": "https://oauth-openshift.apps.example.com/oauth/token"},
+			expectedReason: "OAuthMetadataIssuerDiffer",
+		},
+		{
+			name:           "same issuer, other fields differ",
+			received:       map[string]interface{}{"issuer": "https://oauth-openshift.apps.example.com", "token_endpoint": "https://oauth.old.example.com/oauth/token"},
+			expectedReason: "OAuthMetadataDiffer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := compareOAuthMetadata("https://10.0.0.1/.well-known/oauth-authorization-server", expected, tt.received)
+			if len(tt.expectedReason) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			progressingErr, ok := err.(*common.ControllerProgressingError)
+			if !ok {
+				t.Fatalf("expected a progressing error, got %v", err)
+			}
+			if reason := progressingErr.ToCondition("WellKnownReadyController").Reason; reason != tt.expectedReason {
+				t.Errorf("expected reason %q, got %q", tt.expectedReason, reason)
+			}
+			if tt.expectedReason == "OAuthMetadataIssuerDiffer" && !strings.Contains(err.Error(), "https://oauth-openshift.apps.example.com") {
+				t.Errorf("expected the message to name the expected issuer, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestGetWellKnownFailureThreshold(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     string
+		expected      int
+		expectedError bool
+	}{
+		{
+			name:     "no overrides",
+			expected: defaultWellKnownFailureThreshold,
+		},
+		{
+			name:      "configured",
+			overrides: `{"wellKnownCheck": {"failureThreshold": 5}}`,
+			expected:  5,
+		},
+		{
+			name:      "fail right away",
+			overrides: `{"wellKnownCheck": {"failureThreshold": 1}}`,
+			expected:  1,
+		},
+		{
+			name:          "zero",
+			overrides:     `{"wellKnownCheck": {"failureThreshold": 0}}`,
+			expectedError: true,
+		},
+		{
+			name:          "not a whole number",
+			overrides:     `{"wellKnownCheck": {"failureThreshold": 2.5}}`,
+			expectedError: true,
+		},
+		{
+			name:          "not a number",
+			overrides:     `{"wellKnownCheck": {"failureThreshold": "3"}}`,
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &operatorv1.OperatorSpec{}
+			if len(tt.overrides) > 0 {
+				spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tt.overrides)}
+			}
+
+			got, err := getWellKnownFailureThreshold(spec)
+			if tt.expectedError != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsTransientWellKnownFailure(t *testing.T) {
+	withWellKnownAvailable := func(status operatorv1.ConditionStatus) *operatorv1.OperatorStatus {
+		return &operatorv1.OperatorStatus{
+			Conditions: []operatorv1.OperatorCondition{{Type: "WellKnownAvailable", Status: status}},
+		}
+	}
+
+	tests := []struct {
+		name                string
+		status              *operatorv1.OperatorStatus
+		consecutiveFailures int
+		failureThreshold    int
+		expected            bool
+	}{
+		{
+			name:                "first failure in steady state",
+			status:              withWellKnownAvailable(operatorv1.ConditionTrue),
+			consecutiveFailures: 1,
+			failureThreshold:    3,
+			expected:            true,
+		},
+		{
+			name:                "threshold reached",
+			status:              withWellKnownAvailable(operatorv1.ConditionTrue),
+			consecutiveFailures: 3,
+			failureThreshold:    3,
+		},
+		{
+			name:                "not available yet",
+			status:              withWellKnownAvailable(operatorv1.ConditionFalse),
+			consecutiveFailures: 1,
+			failureThreshold:    3,
+		},
+		{
+			name:                "debounce disabled",
+			status:              withWellKnownAvailable(operatorv1.ConditionTrue),
+			consecutiveFailures: 1,
+			failureThreshold:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientWellKnownFailure(tt.status, tt.consecutiveFailures, tt.failureThreshold); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	start := time.Now()
+	c := &wellKnownReadyController{}
+
+	c.recordFailure(start)
+	c.recordFailure(start.Add(time.Second))
+	c.recordFailure(start.Add(wellKnownFailureInterval - time.Second))
+	if c.consecutiveFailures != 1 {
+		t.Errorf("expected the failures within the interval to count once, got %d", c.consecutiveFailures)
+	}
+
+	c.recordFailure(start.Add(wellKnownFailureInterval))
+	if c.consecutiveFailures != 2 {
+		t.Errorf("expected the failure after the interval to count, got %d", c.consecutiveFailures)
+	}
+}
@@ -0,0 +1,92 @@
+package readiness
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+
+	routev1 "github.com/openshift/api/route/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+// checkRouteWellknownEndpointReady checks the oauth metadata the oauth-server serves through
+// its route, the kube-apiservers serving the expected metadata does not mean that the route
+// the clients are sent to works
+func (c *wellKnownReadyController) checkRouteWellknownEndpointReady(ctx context.Context, route *routev1.Route) error {
+	if len(route.Spec.Host) == 0 {
+		return common.NewControllerProgressingError("RouteWellKnownNotServed", fmt.Errorf("route %s/%s has no host yet", route.Namespace, route.Name), 5*time.Minute)
+	}
+
+	expectedMetadata, err := c.getOAuthMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to get oauth metadata from openshift-config-managed/oauth-openshift ConfigMap: %w (check authentication operator, it is supposed to create this)", err)
+	}
+
+	rt, err := c.routeTransport()
+	if err != nil {
+		return err
+	}
+
+	wellKnown := "https://" + route.Spec.Host + "/.well-known/oauth-authorization-server"
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request to well-known %s: %v", wellKnown, err)
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return common.NewControllerProgressingError("RouteWellKnownNotServed", fmt.Errorf("failed to GET the oauth route endpoint %s: %v (check the ingress operator and the router pods)", wellKnown, err), 5*time.Minute)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return common.NewControllerProgressingError("RouteWellKnownNotServed", fmt.Errorf("the oauth route endpoint %s replied with unexpected status: %s (check the router and the oauth-server pods)", wellKnown, resp.Status), 5*time.Minute)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s body: %v", wellKnown, err)
+	}
+	var receivedValues map[string]interface{}
+	if err := json.Unmarshal(body, &receivedValues); err != nil {
+		return common.NewControllerProgressingError("RouteWellKnownNotServed", fmt.Errorf("failed to unmarshal %s JSON: %v (check that the route is served by the oauth-server)", wellKnown, err), 5*time.Minute)
+	}
+
+	if !reflect.DeepEqual(normalizeMetadataURLs(expectedMetadata), normalizeMetadataURLs(receivedValues)) {
+		return common.NewControllerProgressingError("RouteOAuthMetadataDiffer", fmt.Errorf("the oauth route endpoint %s returns different oauth metadata than is stored in openshift-config-managed/oauth-openshift ConfigMap (check that the oauth-server pods rolled out)", wellKnown), 5*time.Minute)
+	}
+
+	return nil
+}
+
+// routeTransport trusts the system CAs and the CA of the default ingress certificate, the
+// route is served either by a custom certificate or by the default one
+func (c *wellKnownReadyController) routeTransport() (http.RoundTripper, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	defaultIngressCert, err := c.configMapLister.ConfigMaps("openshift-config-managed").Get("default-ingress-cert")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the openshift-config-managed/default-ingress-cert ConfigMap: %w", err)
+	}
+	if ok := rootCAs.AppendCertsFromPEM([]byte(defaultIngressCert.Data["ca-bundle.crt"])); !ok {
+		return nil, fmt.Errorf("the openshift-config-managed/default-ingress-cert ConfigMap contains no certificates in the \"ca-bundle.crt\" key")
+	}
+
+	return utilnet.SetTransportDefaults(&http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+	}), nil
+}
@@ -0,0 +1,110 @@
+package readiness
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+func TestIsWellKnownRouteCheckEnabled(t *testing.T) {
+	for overrides, expected := range map[string]bool{
+		``: false,
+		`{"wellKnownCheck": {"probeRoute": true}}`:  true,
+		`{"wellKnownCheck": {"probeRoute": false}}`: false,
+	} {
+		spec := &operatorv1.OperatorSpec{}
+		if len(overrides) > 0 {
+			spec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(overrides)}
+		}
+		got, err := isWellKnownRouteCheckEnabled(spec)
+		require.NoError(t, err)
+		require.Equal(t, expected, got, overrides)
+	}
+
+	_, err := isWellKnownRouteCheckEnabled(&operatorv1.OperatorSpec{
+		UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"wellKnownCheck": {"probeRoute": "yes"}}`)},
+	})
+	require.Error(t, err)
+}
+
+func TestCheckRouteWellknownEndpointReady(t *testing.T) {
+	const expectedMetadata = `{"issuer": "https://oauth-openshift.apps.example.com", "token_endpoint": "https://oauth-openshift.apps.example.com/oauth/token"}`
+
+	tests := []struct {
+		name           string
+		served         string
+		status         int
+		expectedReason string
+	}{
+		{
+			name:   "matching metadata",
+			served: expectedMetadata,
+			status: http.StatusOK,
+		},
+		{
+			name:           "different metadata",
+			served:         `{"issuer": "https://oauth-openshift.apps.example.com"}`,
+			status:         http.StatusOK,
+			expectedReason: "RouteOAuthMetadataDiffer",
+		},
+		{
+			name:           "not served",
+			served:         `application is not available`,
+			status:         http.StatusServiceUnavailable,
+			expectedReason: "RouteWellKnownNotServed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/.well-known/oauth-authorization-server", r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.served))
+			}))
+			defer server.Close()
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, indexer.Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config-managed", Name: "default-ingress-cert"},
+				Data: map[string]string{
+					"ca-bundle.crt": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+				},
+			}))
+			require.NoError(t, indexer.Add(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config-managed", Name: "oauth-openshift"},
+				Data:       map[string]string{"oauthMetadata": expectedMetadata},
+			}))
+
+			c := &wellKnownReadyController{configMapLister: corev1lister.NewConfigMapLister(indexer)}
+			route := &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "oauth-openshift"},
+				Spec:       routev1.RouteSpec{Host: strings.TrimPrefix(server.URL, "https://")},
+			}
+
+			err := c.checkRouteWellknownEndpointReady(context.Background(), route)
+			if len(tt.expectedReason) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			progressingErr, ok := err.(*common.ControllerProgressingError)
+			require.True(t, ok, "expected a progressing error, got %v", err)
+			require.Equal(t, tt.expectedReason, progressingErr.ToCondition(controllerName).Reason)
+		})
+	}
+}
@@ -110,13 +110,24 @@ func (c *routerCertsDomainValidationController) sync(ctx context.Context, syncCt
 		return nil
 	}
 
+	paused, err := common.IsOperatorReconciliationPaused(c.operatorClient)
+	if err != nil {
+		return err
+	}
+	if paused {
+		// only validate the router certs that were synced already
+		klog.V(4).Infof("reconciliation is paused, skipping the router certs sync")
+		condition = c.validateRouterCertificates()
+		return nil
+	}
+
 	// add syncing for router certs for all cluster ingresses
 	if _, _, err := resourceapply.SyncPartialSecret(
 		ctx,
 		c.secretsClient,
 		syncCtx.Recorder(),
 		"openshift-config-managed", "router-certs",
-		"openshift-authentication", "v4-0-config-system-router-certs",
+		common.TargetNamespace, "v4-0-config-system-router-certs",
 		sets.NewString(ingress.Spec.Domain),
 		nil,
 	); err != nil {
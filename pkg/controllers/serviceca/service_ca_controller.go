@@ -2,6 +2,8 @@ package serviceca
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"time"
 
@@ -16,37 +18,47 @@ import (
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1lister "k8s.io/client-go/listers/core/v1"
 
+	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	configv1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
 )
 
+// serviceCAClusterOperatorName is the cluster operator of the service-ca operator
+const serviceCAClusterOperatorName = "service-ca"
+
 // knownConditionNames lists all condition types used by this controller.
 // These conditions are operated and defaulted by this controller.
 // Any new condition used by this controller sync() loop should be listed here.
 var knownConditionNames = sets.NewString(
 	"OAuthServiceDegraded",
 	"SystemServiceCAConfigDegraded",
+	"ServiceCADependencyUnhealthy",
 )
 
 type serviceCAController struct {
-	serviceLister  corev1lister.ServiceLister
-	secretLister   corev1lister.SecretLister
-	configMaps     corev1client.ConfigMapsGetter
-	operatorClient v1helpers.OperatorClient
+	serviceLister         corev1lister.ServiceLister
+	secretLister          corev1lister.SecretLister
+	clusterOperatorLister configv1listers.ClusterOperatorLister
+	configMaps            corev1client.ConfigMapsGetter
+	operatorClient        v1helpers.OperatorClient
 }
 
 func NewServiceCAController(kubeInformersForTargetNamespace informers.SharedInformerFactory, configInformer configinformers.SharedInformerFactory, configMaps corev1client.ConfigMapsGetter,
 	operatorClient v1helpers.OperatorClient, recorder events.Recorder) factory.Controller {
 	c := &serviceCAController{
-		serviceLister:  kubeInformersForTargetNamespace.Core().V1().Services().Lister(),
-		secretLister:   kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
-		configMaps:     configMaps,
-		operatorClient: operatorClient,
+		serviceLister:         kubeInformersForTargetNamespace.Core().V1().Services().Lister(),
+		secretLister:          kubeInformersForTargetNamespace.Core().V1().Secrets().Lister(),
+		clusterOperatorLister: configInformer.Config().V1().ClusterOperators().Lister(),
+		configMaps:            configMaps,
+		operatorClient:        operatorClient,
 	}
 	return factory.New().WithInformers(
 		kubeInformersForTargetNamespace.Core().V1().Secrets().Informer(),
@@ -54,24 +66,77 @@ func NewServiceCAController(kubeInformersForTargetNamespace informers.SharedInfo
 		kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Informer(),
 		configInformer.Config().V1().Authentications().Informer(),
 		configInformer.Config().V1().Ingresses().Informer(),
-	).ResyncEvery(wait.Jitter(time.Minute, 1.0)).WithSync(c.sync).ToController("ServiceCAController", recorder.WithComponentSuffix("service-ca-controller"))
+	).WithFilteredEventsInformers(
+		common.NamesFilter(serviceCAClusterOperatorName),
+		configInformer.Config().V1().ClusterOperators().Informer(),
+	).ResyncEvery(wait.Jitter(time.Minute, 1.0)).WithSync(heartbeat.Track("ServiceCAController", c.sync)).ToController("ServiceCAController", recorder.WithComponentSuffix("service-ca-controller"))
 }
 
 func (c *serviceCAController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	paused, err := common.IsOperatorReconciliationPaused(c.operatorClient)
+	if err != nil {
+		return err
+	}
+
 	foundConditions := []operatorv1.OperatorCondition{}
 
 	_, serviceConditions := common.GetOAuthServerService(c.serviceLister, "OAuthService")
 	foundConditions = append(foundConditions, serviceConditions...)
 
+	skippedConditions := sets.NewString()
 	if len(foundConditions) == 0 {
-		serviceCAConditions, err := c.getServiceCA(ctx, syncCtx.Recorder())
+		serviceCAConditions, err := c.getServiceCA(ctx, syncCtx.Recorder(), paused)
 		if err != nil {
 			return err
 		}
 		foundConditions = append(foundConditions, serviceCAConditions...)
+	} else {
+		skippedConditions.Insert("SystemServiceCAConfigDegraded")
 	}
+	foundConditions = append(foundConditions, c.getServiceCADependencyCondition())
 
-	return common.UpdateControllerConditions(ctx, c.operatorClient, knownConditionNames, foundConditions)
+	return common.UpdateStagedControllerConditions(ctx, c.operatorClient, knownConditionNames, skippedConditions, foundConditions)
+}
+
+// getServiceCADependencyCondition tells whether the service-ca operator, which issues the
+// serving certificate of the oauth-server and injects the service CA bundle, is healthy so
+// that the conditions depending on it are not mistaken for a fault of this operator
+func (c *serviceCAController) getServiceCADependencyCondition() operatorv1.OperatorCondition {
+	clusterOperator, err := c.clusterOperatorLister.Get(serviceCAClusterOperatorName)
+	if err != nil {
+		return operatorv1.OperatorCondition{
+			Type:    "ServiceCADependencyUnhealthy",
+			Status:  operatorv1.ConditionUnknown,
+			Reason:  "ClusterOperatorUnknown",
+			Message: fmt.Sprintf("Unable to get the %q cluster operator: %v", serviceCAClusterOperatorName, err),
+		}
+	}
+	return serviceCADependencyCondition(clusterOperator)
+}
+
+func serviceCADependencyCondition(clusterOperator *configv1.ClusterOperator) operatorv1.OperatorCondition {
+	unhealthy := func(reason string, condition *configv1.ClusterOperatorStatusCondition) operatorv1.OperatorCondition {
+		return operatorv1.OperatorCondition{
+			Type:   "ServiceCADependencyUnhealthy",
+			Status: operatorv1.ConditionTrue,
+			Reason: reason,
+			Message: fmt.Sprintf("The %s cluster operator reports %s=%s (%s: %s), the serving certificate and the service CA bundle of the oauth-server may not be issued or rotated until it recovers",
+				serviceCAClusterOperatorName, condition.Type, condition.Status, condition.Reason, condition.Message),
+		}
+	}
+
+	if available := configv1helpers.FindStatusCondition(clusterOperator.Status.Conditions, configv1.OperatorAvailable); available != nil && available.Status == configv1.ConditionFalse {
+		return unhealthy("ServiceCAUnavailable", available)
+	}
+	if degraded := configv1helpers.FindStatusCondition(clusterOperator.Status.Conditions, configv1.OperatorDegraded); degraded != nil && degraded.Status == configv1.ConditionTrue {
+		return unhealthy("ServiceCADegraded", degraded)
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:   "ServiceCADependencyUnhealthy",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}
 }
 
 func getServiceCAConfig() *corev1.ConfigMap {
@@ -79,7 +144,7 @@ func getServiceCAConfig() *corev1.ConfigMap {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        "v4-0-config-system-service-ca",
 			Annotations: map[string]string{"service.alpha.openshift.io/inject-cabundle": "true"},
-			Namespace:   "openshift-authentication",
+			Namespace:   common.TargetNamespace,
 			Labels: map[string]string{
 				"app": "oauth-openshift",
 			},
@@ -88,11 +153,13 @@ func getServiceCAConfig() *corev1.ConfigMap {
 	}
 }
 
-func (c *serviceCAController) getServiceCA(ctx context.Context, recorder events.Recorder) ([]operatorv1.OperatorCondition, error) {
-	cm := c.configMaps.ConfigMaps("openshift-authentication")
-	secret := c.secretLister.Secrets("openshift-authentication")
+// getServiceCA checks the service CA config map and the serving cert of the oauth-server,
+// the config map is only created or replaced when the reconciliation is not paused
+func (c *serviceCAController) getServiceCA(ctx context.Context, recorder events.Recorder, paused bool) ([]operatorv1.OperatorCondition, error) {
+	cm := c.configMaps.ConfigMaps(common.TargetNamespace)
+	secret := c.secretLister.Secrets(common.TargetNamespace)
 	serviceCA, err := cm.Get(ctx, "v4-0-config-system-service-ca", metav1.GetOptions{})
-	if errors.IsNotFound(err) {
+	if errors.IsNotFound(err) && !paused {
 		_, err = cm.Create(ctx, getServiceCAConfig(), metav1.CreateOptions{})
 	}
 	if err != nil {
@@ -113,6 +180,14 @@ func (c *serviceCAController) getServiceCA(ctx context.Context, recorder events.
 		}}, nil
 	}
 
+	if serviceCA.Annotations["service.alpha.openshift.io/inject-cabundle"] != "true" && paused {
+		return []operatorv1.OperatorCondition{{
+			Type:    "SystemServiceCAConfigDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "MissingInjectionAnnotation",
+			Message: fmt.Sprintf("Config %q lacks the inject-cabundle annotation, it is not replaced while the reconciliation is paused", serviceCA.Name),
+		}}, nil
+	}
 	if serviceCA.Annotations["service.alpha.openshift.io/inject-cabundle"] != "true" {
 		// return fmt.Errorf("config map missing injection annotation: %#v", ca)
 		// delete the service CA config map so that it is replaced with the proper one in next reconcile loop
@@ -125,7 +200,8 @@ func (c *serviceCAController) getServiceCA(ctx context.Context, recorder events.
 		return nil, factory.SyntheticRequeueError
 	}
 
-	if _, err = secret.Get("v4-0-config-system-serving-cert"); err != nil {
+	servingCert, err := secret.Get("v4-0-config-system-serving-cert")
+	if err != nil {
 		return []operatorv1.OperatorCondition{{
 			Type:    "SystemServiceCAConfigDegraded",
 			Status:  operatorv1.ConditionTrue,
@@ -134,5 +210,40 @@ func (c *serviceCAController) getServiceCA(ctx context.Context, recorder events.
 		}}, nil
 	}
 
+	if err := validateServingCertSecret(servingCert); err != nil {
+		return []operatorv1.OperatorCondition{{
+			Type:    "SystemServiceCAConfigDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "InvalidSystemServingCert",
+			Message: fmt.Sprintf("The system serving cert secret %q is not usable by the oauth-server: %v (check the service-ca operator, it is supposed to issue this)", servingCert.Name, err),
+		}}, nil
+	}
+
 	return nil, nil
 }
+
+// validateServingCertSecret checks that the serving cert secret issued by the service-ca
+// operator holds a matching certificate and key that are currently valid
+func validateServingCertSecret(secret *corev1.Secret) error {
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return fmt.Errorf("%s or %s is empty", corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid certificate and key pair: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+	if now := time.Now(); now.After(leaf.NotAfter) {
+		return fmt.Errorf("the certificate expired at %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	} else if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("the certificate is not valid before %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
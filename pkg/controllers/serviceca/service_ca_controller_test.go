@@ -0,0 +1,122 @@
+package serviceca
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestValidateServingCertSecret(t *testing.T) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("oauth-openshift.openshift-authentication.svc", nil, nil)
+	require.NoError(t, err)
+	_, otherKeyPEM, err := certutil.GenerateSelfSignedCertKey("other.openshift-authentication.svc", nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		data          map[string][]byte
+		expectedError bool
+	}{
+		{
+			name: "valid key pair",
+			data: map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+		},
+		{
+			name:          "empty secret",
+			expectedError: true,
+		},
+		{
+			name:          "missing key",
+			data:          map[string][]byte{corev1.TLSCertKey: certPEM},
+			expectedError: true,
+		},
+		{
+			name:          "garbage certificate",
+			data:          map[string][]byte{corev1.TLSCertKey: []byte("not a cert"), corev1.TLSPrivateKeyKey: keyPEM},
+			expectedError: true,
+		},
+		{
+			name:          "key does not match the certificate",
+			data:          map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: otherKeyPEM},
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-authentication", Name: "v4-0-config-system-serving-cert"},
+				Data:       tt.data,
+			}
+
+			err := validateServingCertSecret(secret)
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceCADependencyCondition(t *testing.T) {
+	tests := []struct {
+		name           string
+		conditions     []configv1.ClusterOperatorStatusCondition
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name: "healthy",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionFalse},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name:           "no conditions yet",
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "AsExpected",
+		},
+		{
+			name: "degraded",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Reason: "SigningKeyExpired", Message: "the signing key expired"},
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ServiceCADegraded",
+		},
+		{
+			name: "unavailable takes precedence",
+			conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionFalse, Reason: "NoPods", Message: "the signing key expired"},
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue},
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ServiceCAUnavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := serviceCADependencyCondition(&configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: "service-ca"},
+				Status:     configv1.ClusterOperatorStatus{Conditions: tt.conditions},
+			})
+			require.Equal(t, "ServiceCADependencyUnhealthy", condition.Type)
+			require.Equal(t, tt.expectedStatus, condition.Status)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+			if tt.expectedStatus == operatorv1.ConditionTrue {
+				require.Contains(t, condition.Message, "the signing key expired")
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package statedump
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	routeinformer "github.com/openshift/client-go/route/informers/externalversions/route/v1"
+	routev1listers "github.com/openshift/client-go/route/listers/route/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+const (
+	// StateDumpAnnotation can be set on the operator config to have the operator dump its
+	// view of the oauth-server into the state config map, the dump is refreshed whenever
+	// the value of the annotation changes
+	StateDumpAnnotation = "authentication.operator.openshift.io/dump-state"
+
+	// stateConfigMapName is the config map in the operator namespace that holds the dump,
+	// must-gather collects it along with the rest of the namespace
+	stateConfigMapName = "authentication-operator-state"
+	// stateRequestKey records the value of the annotation the dump was taken for
+	stateRequestKey = "request"
+
+	redactedValue = "<redacted>"
+)
+
+// sensitiveKeyPattern matches the keys of the oauth-server config that may carry secret material
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(secret|password|token|credential)`)
+
+// stateDumpController writes a sanitized snapshot of the desired and observed state
+// of the oauth-server on request so that support bundles don't have to reconstruct it
+type stateDumpController struct {
+	operatorClient   v1helpers.OperatorClient
+	deploymentLister appsv1listers.DeploymentLister
+	configMapLister  corev1listers.ConfigMapLister
+	routeLister      routev1listers.RouteLister
+	configMaps       corev1client.ConfigMapsGetter
+}
+
+func NewStateDumpController(
+	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
+	routeInformer routeinformer.RouteInformer,
+	configMaps corev1client.ConfigMapsGetter,
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	targetInformers := kubeInformersForNamespaces.InformersFor(common.TargetNamespace)
+	c := &stateDumpController{
+		operatorClient:   operatorClient,
+		deploymentLister: targetInformers.Apps().V1().Deployments().Lister(),
+		configMapLister:  targetInformers.Core().V1().ConfigMaps().Lister(),
+		routeLister:      routeInformer.Lister(),
+		configMaps:       configMaps,
+	}
+
+	return factory.New().
+		WithInformers(
+			operatorClient.Informer(),
+			kubeInformersForNamespaces.InformersFor(common.OperatorNamespace).Core().V1().ConfigMaps().Informer(),
+		).
+		WithSync(c.sync).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("StateDumpController", eventRecorder.WithComponentSuffix("state-dump-controller"))
+}
+
+func (c *stateDumpController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorConfigMeta, err := c.operatorClient.GetObjectMeta()
+	if err != nil {
+		return err
+	}
+
+	request, requested := operatorConfigMeta.GetAnnotations()[StateDumpAnnotation]
+	if !requested {
+		return nil
+	}
+	if common.IsReconciliationPaused(operatorConfigMeta) {
+		klog.V(4).Infof("reconciliation is paused, skipping the state dump")
+		return nil
+	}
+
+	existing, err := c.configMaps.ConfigMaps(common.OperatorNamespace).Get(ctx, stateConfigMapName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && existing.Data[stateRequestKey] == request {
+		// already dumped for this request
+		return nil
+	}
+
+	_, operatorStatus, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	data, err := c.dumpState(operatorStatus)
+	if err != nil {
+		return fmt.Errorf("failed to dump the operator state: %w", err)
+	}
+	data[stateRequestKey] = request
+
+	stateConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: common.OperatorNamespace,
+			Name:      stateConfigMapName,
+		},
+		Data: data,
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMaps, syncCtx.Recorder(), stateConfigMap); err != nil {
+		return err
+	}
+
+	syncCtx.Recorder().Eventf("OperatorStateDumped", "Dumped the operator state to %s/%s as requested by the %q annotation", common.OperatorNamespace, stateConfigMapName, StateDumpAnnotation)
+	return nil
+}
+
+// dumpState collects the state of the oauth-server as the operator sees it, the parts that
+// are missing are noted in the dump instead of failing it as it's mostly needed when
+// something is broken
+func (c *stateDumpController) dumpState(operatorStatus *operatorv1.OperatorStatus) (map[string]string, error) {
+	data := map[string]string{}
+
+	conditions, err := yaml.Marshal(operatorStatus.Conditions)
+	if err != nil {
+		return nil, err
+	}
+	data["conditions.yaml"] = string(conditions)
+
+	if deployment, err := c.deploymentLister.Deployments(common.TargetNamespace).Get("oauth-openshift"); err != nil {
+		data["deployment.yaml"] = fmt.Sprintf("# unable to get the oauth-openshift deployment: %v\n", err)
+	} else {
+		deployment = deployment.DeepCopy()
+		// the proxy and the extra environment variables may carry credentials
+		for i := range deployment.Spec.Template.Spec.Containers {
+			for j := range deployment.Spec.Template.Spec.Containers[i].Env {
+				if env := &deployment.Spec.Template.Spec.Containers[i].Env[j]; len(env.Value) > 0 {
+					env.Value = redactedValue
+				}
+			}
+		}
+		deploymentState := map[string]interface{}{
+			// the pod template annotations carry the hash of the tracked resource versions
+			"generation":  deployment.Generation,
+			"annotations": deployment.Annotations,
+			"spec":        deployment.Spec,
+			"status":      deployment.Status,
+		}
+		deploymentYAML, err := yaml.Marshal(deploymentState)
+		if err != nil {
+			return nil, err
+		}
+		data["deployment.yaml"] = string(deploymentYAML)
+	}
+
+	if cliConfig, err := c.configMapLister.ConfigMaps(common.TargetNamespace).Get("v4-0-config-system-cliconfig"); err != nil {
+		data["oauth-config.yaml"] = fmt.Sprintf("# unable to get the oauth-server config: %v\n", err)
+	} else {
+		oauthConfig, err := redactConfig([]byte(cliConfig.Data["v4-0-config-system-cliconfig"]))
+		if err != nil {
+			data["oauth-config.yaml"] = fmt.Sprintf("# unable to decode the oauth-server config: %v\n", err)
+		} else {
+			data["oauth-config.yaml"] = string(oauthConfig)
+		}
+	}
+
+	if route, err := c.routeLister.Routes(common.TargetNamespace).Get("oauth-openshift"); err != nil {
+		data["route-host"] = fmt.Sprintf("unable to get the oauth-openshift route: %v", err)
+	} else {
+		hosts := []string{"spec: " + route.Spec.Host}
+		for _, ingress := range route.Status.Ingress {
+			hosts = append(hosts, fmt.Sprintf("router %s: %s", ingress.RouterName, ingress.Host))
+		}
+		data["route-host"] = strings.Join(hosts, "\n")
+	}
+
+	return data, nil
+}
+
+// redactConfig returns the oauth-server config in YAML with the values of the sensitive
+// keys redacted, file references are kept as they only point to the mounted secrets
+func redactConfig(configJSON []byte) ([]byte, error) {
+	var config interface{}
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(redact(config, false))
+}
+
+func redact(obj interface{}, sensitive bool) interface{} {
+	switch typed := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range typed {
+			if strings.HasSuffix(strings.ToLower(k), "file") {
+				continue
+			}
+			typed[k] = redact(v, sensitive || sensitiveKeyPattern.MatchString(k))
+		}
+		return typed
+	case []interface{}:
+		for i := range typed {
+			typed[i] = redact(typed[i], sensitive)
+		}
+		return typed
+	case string:
+		if sensitive && len(typed) > 0 {
+			return redactedValue
+		}
+		return typed
+	default:
+		return obj
+	}
+}
@@ -0,0 +1,166 @@
+package statusconsistency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	configv1listers "github.com/openshift/client-go/config/listers/config/v1"
+	configv1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	// maxDegradedInertia is the longest the cluster operator status controller holds back
+	// Degraded=True, it's the inertia of the workload deployment conditions
+	maxDegradedInertia = 30 * time.Minute
+	// divergenceGracePeriod covers the time the status controller needs to pick up a change
+	divergenceGracePeriod = 5 * time.Minute
+)
+
+// clusterConditionDefaults are the aggregated conditions that the cluster operator
+// status controller unions from the operator conditions and their default status
+var clusterConditionDefaults = map[configv1.ClusterStatusConditionType]operatorv1.ConditionStatus{
+	configv1.OperatorDegraded:    operatorv1.ConditionFalse,
+	configv1.OperatorProgressing: operatorv1.ConditionFalse,
+	configv1.OperatorAvailable:   operatorv1.ConditionTrue,
+	configv1.OperatorUpgradeable: operatorv1.ConditionTrue,
+}
+
+// statusConsistencyController logs when the status of the authentication cluster operator
+// does not follow the conditions of the operator config, users only see the former
+type statusConsistencyController struct {
+	operatorClient        v1helpers.OperatorClient
+	clusterOperatorLister configv1listers.ClusterOperatorLister
+
+	now func() time.Time
+	// divergedSince tracks when each aggregated condition started to diverge
+	divergedSince map[string]time.Time
+	// reported are the divergences that were already reported in an event
+	reported map[string]bool
+}
+
+func NewStatusConsistencyController(
+	configInformers configinformers.SharedInformerFactory,
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &statusConsistencyController{
+		operatorClient:        operatorClient,
+		clusterOperatorLister: configInformers.Config().V1().ClusterOperators().Lister(),
+		now:                   time.Now,
+		divergedSince:         map[string]time.Time{},
+		reported:              map[string]bool{},
+	}
+
+	return factory.New().
+		WithInformers(
+			configInformers.Config().V1().ClusterOperators().Informer(),
+			operatorClient.Informer(),
+		).
+		WithSync(c.sync).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController("StatusConsistencyController", eventRecorder.WithComponentSuffix("status-consistency-controller"))
+}
+
+func (c *statusConsistencyController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	operatorSpec, operatorStatus, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+	if operatorSpec.ManagementState != operatorv1.Managed {
+		// the status controller reports Unknown for anything else
+		return nil
+	}
+
+	clusterOperator, err := c.clusterOperatorLister.Get("authentication")
+	if errors.IsNotFound(err) {
+		// the status controller creates it
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	diverged := divergedConditions(operatorStatus.Conditions, clusterOperator.Status.Conditions)
+
+	now := c.now()
+	for conditionType := range c.divergedSince {
+		if _, ok := diverged[conditionType]; !ok {
+			delete(c.divergedSince, conditionType)
+			delete(c.reported, conditionType)
+		}
+	}
+	for _, conditionType := range sortedKeys(diverged) {
+		since, ok := c.divergedSince[conditionType]
+		if !ok {
+			c.divergedSince[conditionType] = now
+			continue
+		}
+		if now.Sub(since) < divergenceGracePeriod {
+			continue
+		}
+
+		klog.Errorf("The %s condition of clusteroperators.config.openshift.io/authentication diverged from the operator conditions %s ago: %s", conditionType, now.Sub(since).Round(time.Second), diverged[conditionType])
+		if !c.reported[conditionType] {
+			syncCtx.Recorder().Warningf("ClusterOperatorStatusDiverged", "The %s condition of the authentication cluster operator does not reflect the operator conditions: %s", conditionType, diverged[conditionType])
+			c.reported[conditionType] = true
+		}
+	}
+
+	return nil
+}
+
+// divergedConditions returns the aggregated conditions of the cluster operator that
+// differ from the union of the operator conditions, along with the reason. The union is
+// computed the same way as in the cluster operator status controller, the Degraded
+// condition is allowed to lag behind by the inertia of the status controller.
+func divergedConditions(operatorConditions []operatorv1.OperatorCondition, clusterOperatorConditions []configv1.ClusterOperatorStatusCondition) map[string]string {
+	diverged := map[string]string{}
+
+	for conditionType, defaultStatus := range clusterConditionDefaults {
+		clusterCondition := configv1helpers.FindStatusCondition(clusterOperatorConditions, conditionType)
+		if clusterCondition == nil {
+			diverged[string(conditionType)] = "the condition is missing"
+			continue
+		}
+
+		expected := status.UnionClusterCondition(string(conditionType), defaultStatus, nil, operatorConditions...)
+		if conditionType != configv1.OperatorDegraded {
+			if clusterCondition.Status != expected.Status {
+				diverged[string(conditionType)] = fmt.Sprintf("the cluster operator reports %s but the operator conditions union to %s (%s)", clusterCondition.Status, expected.Status, expected.Reason)
+			}
+			continue
+		}
+
+		// inertia only delays Degraded=True, never clears it
+		sustained := status.UnionClusterCondition(string(conditionType), defaultStatus, func(operatorv1.OperatorCondition) time.Duration { return maxDegradedInertia }, operatorConditions...)
+		switch {
+		case clusterCondition.Status == configv1.ConditionTrue && expected.Status == configv1.ConditionFalse:
+			diverged[string(conditionType)] = "the cluster operator reports Degraded=True but none of the operator conditions is degraded"
+		case clusterCondition.Status == configv1.ConditionFalse && sustained.Status == configv1.ConditionTrue:
+			diverged[string(conditionType)] = fmt.Sprintf("the cluster operator reports Degraded=False but the operator conditions have been degraded for more than %s (%s)", maxDegradedInertia, sustained.Reason)
+		}
+	}
+
+	return diverged
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
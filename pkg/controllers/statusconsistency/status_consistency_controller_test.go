@@ -0,0 +1,85 @@
+package statusconsistency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+)
+
+func TestDivergedConditions(t *testing.T) {
+	clusterConditions := func(degraded, progressing, available, upgradeable configv1.ConditionStatus) []configv1.ClusterOperatorStatusCondition {
+		return []configv1.ClusterOperatorStatusCondition{
+			{Type: configv1.OperatorDegraded, Status: degraded},
+			{Type: configv1.OperatorProgressing, Status: progressing},
+			{Type: configv1.OperatorAvailable, Status: available},
+			{Type: configv1.OperatorUpgradeable, Status: upgradeable},
+		}
+	}
+	operatorCondition := func(conditionType string, status operatorv1.ConditionStatus, age time.Duration) operatorv1.OperatorCondition {
+		return operatorv1.OperatorCondition{Type: conditionType, Status: status, LastTransitionTime: metav1.NewTime(time.Now().Add(-age))}
+	}
+	healthy := []operatorv1.OperatorCondition{
+		operatorCondition("OAuthServerDeploymentDegraded", operatorv1.ConditionFalse, time.Hour),
+		operatorCondition("OAuthServerDeploymentProgressing", operatorv1.ConditionFalse, time.Hour),
+		operatorCondition("OAuthServerDeploymentAvailable", operatorv1.ConditionTrue, time.Hour),
+		operatorCondition("IdentityProviderTypesUpgradeable", operatorv1.ConditionTrue, time.Hour),
+	}
+
+	tests := []struct {
+		name               string
+		operatorConditions []operatorv1.OperatorCondition
+		clusterConditions  []configv1.ClusterOperatorStatusCondition
+		expectedDiverged   []string
+	}{
+		{
+			name:               "consistent",
+			operatorConditions: healthy,
+			clusterConditions:  clusterConditions(configv1.ConditionFalse, configv1.ConditionFalse, configv1.ConditionTrue, configv1.ConditionTrue),
+		},
+		{
+			name:               "cluster operator not available",
+			operatorConditions: healthy,
+			clusterConditions:  clusterConditions(configv1.ConditionFalse, configv1.ConditionFalse, configv1.ConditionFalse, configv1.ConditionTrue),
+			expectedDiverged:   []string{"Available"},
+		},
+		{
+			name: "degraded within the inertia",
+			operatorConditions: append([]operatorv1.OperatorCondition{
+				operatorCondition("WellKnownReadyControllerDegraded", operatorv1.ConditionTrue, 10*time.Minute),
+			}, healthy...),
+			clusterConditions: clusterConditions(configv1.ConditionFalse, configv1.ConditionFalse, configv1.ConditionTrue, configv1.ConditionTrue),
+		},
+		{
+			name: "degraded beyond the inertia",
+			operatorConditions: append([]operatorv1.OperatorCondition{
+				operatorCondition("WellKnownReadyControllerDegraded", operatorv1.ConditionTrue, time.Hour),
+			}, healthy...),
+			clusterConditions: clusterConditions(configv1.ConditionFalse, configv1.ConditionFalse, configv1.ConditionTrue, configv1.ConditionTrue),
+			expectedDiverged:  []string{"Degraded"},
+		},
+		{
+			name:               "degraded without degraded conditions",
+			operatorConditions: healthy,
+			clusterConditions:  clusterConditions(configv1.ConditionTrue, configv1.ConditionFalse, configv1.ConditionTrue, configv1.ConditionTrue),
+			expectedDiverged:   []string{"Degraded"},
+		},
+		{
+			name:               "missing conditions",
+			operatorConditions: healthy,
+			clusterConditions:  clusterConditions(configv1.ConditionFalse, configv1.ConditionFalse, configv1.ConditionTrue, configv1.ConditionTrue)[:2],
+			expectedDiverged:   []string{"Available", "Upgradeable"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diverged := divergedConditions(tt.operatorConditions, tt.clusterConditions)
+			require.ElementsMatch(t, tt.expectedDiverged, sortedKeys(diverged), "%v", diverged)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
 
 	configinformers "github.com/openshift/client-go/config/informers/externalversions/config/v1"
 	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
@@ -28,24 +29,28 @@ type trustDistributionController struct {
 	secretsLister corev1listers.SecretLister
 
 	ingressLister configlistersv1.IngressLister
+
+	operatorClient v1helpers.OperatorClient
 }
 
 func NewTrustDistributionController(
 	cmClient corev1client.ConfigMapsGetter,
 	kubeInformersForNamespaces v1helpers.KubeInformersForNamespaces,
 	ingressInformer configinformers.IngressInformer,
+	operatorClient v1helpers.OperatorClient,
 	eventsRecorder events.Recorder,
 ) factory.Controller {
 	c := &trustDistributionController{
-		configMaps:    cmClient,
-		secretsLister: kubeInformersForNamespaces.SecretLister(),
-		ingressLister: ingressInformer.Lister(),
+		configMaps:     cmClient,
+		secretsLister:  kubeInformersForNamespaces.SecretLister(),
+		ingressLister:  ingressInformer.Lister(),
+		operatorClient: operatorClient,
 	}
 
 	return factory.New().
 		WithInformers(
 			ingressInformer.Informer(),
-			kubeInformersForNamespaces.InformersFor("openshift-authentication").Core().V1().Secrets().Informer(),
+			kubeInformersForNamespaces.InformersFor(common.TargetNamespace).Core().V1().Secrets().Informer(),
 			kubeInformersForNamespaces.InformersFor("openshift-config-managed").Core().V1().Secrets().Informer(),
 		).
 		WithSync(c.sync).
@@ -61,7 +66,7 @@ func (c *trustDistributionController) sync(ctx context.Context, syncContext fact
 
 	certBundle, _, _, err := common.GetActiveRouterCertKeyBytes(c.secretsLister,
 		ingressConfig,
-		"openshift-authentication",
+		common.TargetNamespace,
 		"v4-0-config-system-router-certs",
 		"v4-0-config-system-custom-router-certs",
 	)
@@ -88,6 +93,13 @@ func (c *trustDistributionController) sync(ctx context.Context, syncContext fact
 		return utilerrors.NewAggregate(errs)
 	}
 
+	if paused, err := common.IsOperatorReconciliationPaused(c.operatorClient); err != nil {
+		return err
+	} else if paused {
+		klog.V(4).Infof("reconciliation is paused, skipping sync")
+		return nil
+	}
+
 	_, _, err = resourceapply.ApplyConfigMap(ctx,
 		c.configMaps,
 		syncContext.Recorder(),
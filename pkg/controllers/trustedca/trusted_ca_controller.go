@@ -0,0 +1,163 @@
+package trustedca
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+const (
+	controllerName = "OperatorTrustedCAController"
+
+	operatorNamespace     = common.OperatorNamespace
+	trustedCAConfigMap    = "trusted-ca-bundle"
+	trustedCABundleKey    = "ca-bundle.crt"
+	injectTrustedCALabel  = "config.openshift.io/inject-trusted-cabundle"
+	bundleInjectionMaxAge = 5 * time.Minute
+)
+
+// operatorTrustedCAController makes sure that the cluster trust bundle gets injected
+// into the operator's namespace. The operator uses the bundle as its system trust
+// store, e.g. when checking the health of the oauth-openshift route.
+type operatorTrustedCAController struct {
+	configMaps      corev1client.ConfigMapsGetter
+	configMapLister corev1listers.ConfigMapLister
+	operatorClient  v1helpers.OperatorClient
+}
+
+func NewOperatorTrustedCAController(
+	configMaps corev1client.ConfigMapsGetter,
+	configMapInformer corev1informers.ConfigMapInformer,
+	operatorClient v1helpers.OperatorClient,
+	eventRecorder events.Recorder,
+) factory.Controller {
+	c := &operatorTrustedCAController{
+		configMaps:      configMaps,
+		configMapLister: configMapInformer.Lister(),
+		operatorClient:  operatorClient,
+	}
+
+	return factory.New().
+		WithFilteredEventsInformers(
+			common.NamesFilter(trustedCAConfigMap),
+			configMapInformer.Informer(),
+		).
+		WithSync(c.sync).
+		WithSyncDegradedOnError(operatorClient).
+		ResyncEvery(wait.Jitter(time.Minute, 1.0)).
+		ToController(controllerName, eventRecorder.WithComponentSuffix("operator-trusted-ca-controller"))
+}
+
+func (c *operatorTrustedCAController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	_, operatorStatus, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	paused, err := common.IsOperatorReconciliationPaused(c.operatorClient)
+	if err != nil {
+		return err
+	}
+
+	var cm *corev1.ConfigMap
+	if paused {
+		// only check the injected bundle while paused
+		cm, err = c.configMapLister.ConfigMaps(operatorNamespace).Get(trustedCAConfigMap)
+		if apierrors.IsNotFound(err) {
+			cm, err = &corev1.ConfigMap{}, nil
+		}
+	} else {
+		cm, err = c.ensureTrustedCAConfigMap(ctx, syncCtx.Recorder())
+	}
+	if err != nil {
+		return err
+	}
+
+	progressingCondition := operatorv1.OperatorCondition{
+		Type:   common.ControllerProgressingConditionName(controllerName),
+		Status: operatorv1.ConditionFalse,
+	}
+	if err := validateInjectedBundle(cm); err != nil {
+		progressingErr, ok := err.(*common.ControllerProgressingError)
+		if !ok || progressingErr.IsDegraded(controllerName, operatorStatus) {
+			return err
+		}
+		progressingCondition = progressingErr.ToCondition(controllerName)
+	}
+
+	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(progressingCondition))
+	return err
+}
+
+// ensureTrustedCAConfigMap creates the trusted CA config map or labels the existing one
+// for injection, it never touches the data as these are owned by the injector
+func (c *operatorTrustedCAController) ensureTrustedCAConfigMap(ctx context.Context, recorder events.Recorder) (*corev1.ConfigMap, error) {
+	cm, err := c.configMapLister.ConfigMaps(operatorNamespace).Get(trustedCAConfigMap)
+	if apierrors.IsNotFound(err) {
+		cm, err = c.configMaps.ConfigMaps(operatorNamespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: operatorNamespace,
+				Name:      trustedCAConfigMap,
+				Labels:    map[string]string{injectTrustedCALabel: "true"},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the %s/%s config map: %w", operatorNamespace, trustedCAConfigMap, err)
+		}
+		recorder.Eventf("TrustedCAConfigMapCreated", "Created the %s/%s config map for the cluster trust bundle injection", operatorNamespace, trustedCAConfigMap)
+		return cm, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cm.Labels[injectTrustedCALabel] == "true" {
+		return cm, nil
+	}
+
+	cm = cm.DeepCopy()
+	if cm.Labels == nil {
+		cm.Labels = map[string]string{}
+	}
+	cm.Labels[injectTrustedCALabel] = "true"
+	cm, err = c.configMaps.ConfigMaps(operatorNamespace).Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to label the %s/%s config map for the trust bundle injection: %w", operatorNamespace, trustedCAConfigMap, err)
+	}
+	recorder.Eventf("TrustedCAConfigMapLabeled", "Labeled the %s/%s config map for the cluster trust bundle injection", operatorNamespace, trustedCAConfigMap)
+
+	return cm, nil
+}
+
+func validateInjectedBundle(cm *corev1.ConfigMap) error {
+	bundle := cm.Data[trustedCABundleKey]
+	if len(bundle) == 0 {
+		return common.NewControllerProgressingError(
+			"TrustBundleNotInjected",
+			fmt.Errorf("the cluster trust bundle has not been injected into the %s/%s config map yet, the operator won't be able to verify the serving certificates of the oauth-openshift route (check the network operator that handles the injection)", operatorNamespace, trustedCAConfigMap),
+			bundleInjectionMaxAge,
+		)
+	}
+
+	if ok := x509.NewCertPool().AppendCertsFromPEM([]byte(bundle)); !ok {
+		return fmt.Errorf("no PEM certificates found in the %q key of the %s/%s config map", trustedCABundleKey, operatorNamespace, trustedCAConfigMap)
+	}
+
+	return nil
+}
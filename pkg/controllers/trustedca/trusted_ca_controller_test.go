@@ -0,0 +1,159 @@
+package trustedca
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+)
+
+func TestOperatorTrustedCAController(t *testing.T) {
+	ca, err := crypto.MakeSelfSignedCAConfig("test-ca", 1)
+	require.NoError(t, err)
+	caPEM, _, err := ca.GetPEMBytes()
+	require.NoError(t, err)
+
+	progressingType := common.ControllerProgressingConditionName(controllerName)
+	notInjectedMessage := validateInjectedBundle(&corev1.ConfigMap{}).Error()
+
+	tests := []struct {
+		name               string
+		existingCM         *corev1.ConfigMap
+		paused             bool
+		previousConditions []operatorv1.OperatorCondition
+		expectedProgress   operatorv1.ConditionStatus
+		expectedErr        bool
+	}{
+		{
+			name:             "config map is missing",
+			expectedProgress: operatorv1.ConditionTrue,
+		},
+		{
+			name:             "config map is not labeled for injection",
+			existingCM:       testConfigMap(nil, map[string]string{trustedCABundleKey: string(caPEM)}),
+			expectedProgress: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "bundle was injected",
+			existingCM:       testConfigMap(map[string]string{injectTrustedCALabel: "true"}, map[string]string{trustedCABundleKey: string(caPEM)}),
+			expectedProgress: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "bundle was not injected yet",
+			existingCM:       testConfigMap(map[string]string{injectTrustedCALabel: "true"}, nil),
+			expectedProgress: operatorv1.ConditionTrue,
+		},
+		{
+			name:       "bundle was not injected for too long",
+			existingCM: testConfigMap(map[string]string{injectTrustedCALabel: "true"}, nil),
+			previousConditions: []operatorv1.OperatorCondition{{
+				Type:               progressingType,
+				Status:             operatorv1.ConditionTrue,
+				Reason:             "TrustBundleNotInjected",
+				Message:            notInjectedMessage,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * bundleInjectionMaxAge)),
+			}},
+			expectedErr: true,
+		},
+		{
+			name:             "config map is missing while paused",
+			paused:           true,
+			expectedProgress: operatorv1.ConditionTrue,
+		},
+		{
+			name:             "config map is not labeled for injection while paused",
+			existingCM:       testConfigMap(nil, nil),
+			paused:           true,
+			expectedProgress: operatorv1.ConditionTrue,
+		},
+		{
+			name:        "bundle contains garbage",
+			existingCM:  testConfigMap(map[string]string{injectTrustedCALabel: "true"}, map[string]string{trustedCABundleKey: "not a certificate"}),
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{}
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tt.existingCM != nil {
+				objects = append(objects, tt.existingCM)
+				require.NoError(t, indexer.Add(tt.existingCM))
+			}
+			kubeClient := fake.NewSimpleClientset(objects...)
+
+			annotations := map[string]string{}
+			if tt.paused {
+				annotations[common.ReconciliationPausedAnnotation] = ""
+			}
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+				&metav1.ObjectMeta{Name: "cluster", Annotations: annotations},
+				&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+				&operatorv1.OperatorStatus{Conditions: tt.previousConditions},
+				nil,
+			)
+
+			c := &operatorTrustedCAController{
+				configMaps:      kubeClient.CoreV1(),
+				configMapLister: corev1listers.NewConfigMapLister(indexer),
+				operatorClient:  operatorClient,
+			}
+
+			err := c.sync(context.Background(), factory.NewSyncContext("testctx", events.NewInMemoryRecorder("test-recorder")))
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			cm, err := kubeClient.CoreV1().ConfigMaps(operatorNamespace).Get(context.Background(), trustedCAConfigMap, metav1.GetOptions{})
+			switch {
+			case tt.paused && tt.existingCM == nil:
+				require.True(t, apierrors.IsNotFound(err), "the config map must not be created while paused")
+			case tt.paused:
+				require.NoError(t, err)
+				require.Equal(t, tt.existingCM, cm, "the config map must not be touched while paused")
+			default:
+				require.NoError(t, err)
+				require.Equal(t, "true", cm.Labels[injectTrustedCALabel])
+				if tt.existingCM != nil {
+					require.Equal(t, tt.existingCM.Data, cm.Data, "the injected data must not be touched")
+				}
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			require.NoError(t, err)
+			condition := v1helpers.FindOperatorCondition(status.Conditions, progressingType)
+			require.NotNil(t, condition)
+			require.Equal(t, tt.expectedProgress, condition.Status)
+		})
+	}
+}
+
+func testConfigMap(labels, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: operatorNamespace,
+			Name:      trustedCAConfigMap,
+			Labels:    labels,
+		},
+		Data: data,
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/status"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 	"github.com/openshift/cluster-authentication-operator/pkg/operator/assets"
 )
 
@@ -105,6 +106,13 @@ func (c *webhookAuthenticatorController) sync(ctx context.Context, syncCtx facto
 		}
 	}
 
+	if paused, err := common.IsOperatorReconciliationPaused(c.operatorClient); err != nil {
+		return err
+	} else if paused {
+		klog.V(4).Infof("reconciliation is paused, skipping sync")
+		return nil
+	}
+
 	authConfig, err := c.authentication.Get(ctx, "cluster", metav1.GetOptions{})
 	if err != nil {
 		return err
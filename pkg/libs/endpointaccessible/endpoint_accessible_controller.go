@@ -3,6 +3,8 @@ package endpointaccessible
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -24,11 +26,36 @@ type endpointAccessibleController struct {
 	endpointListFn         EndpointListFunc
 	getTLSConfigFn         EndpointTLSConfigFunc
 	availableConditionName string
+	// certHostMismatchReason is the reason of the unavailable condition when the endpoints
+	// serve certificates that are valid, but not for their host
+	certHostMismatchReason string
 }
 
 type EndpointListFunc func() ([]string, error)
 type EndpointTLSConfigFunc func() (*tls.Config, error)
 
+// Option customizes the endpoint accessible controller
+type Option func(*endpointAccessibleController)
+
+// WithCertHostMismatchReason sets the reason reported when none of the endpoints serves
+// a certificate that covers its host
+func WithCertHostMismatchReason(reason string) Option {
+	return func(c *endpointAccessibleController) {
+		c.certHostMismatchReason = reason
+	}
+}
+
+// certHostMismatchError tells a certificate that does not cover the host of the endpoint
+// apart from the certificates that are not trusted
+type certHostMismatchError struct {
+	endpoint string
+	err      x509.HostnameError
+}
+
+func (e *certHostMismatchError) Error() string {
+	return fmt.Sprintf("%q serves a certificate that does not cover its host: %v", e.endpoint, e.err.Error())
+}
+
 // NewEndpointAccessibleController returns a controller that checks if the endpoints
 // listed by endpointListFn are reachable
 func NewEndpointAccessibleController(
@@ -38,6 +65,7 @@ func NewEndpointAccessibleController(
 	getTLSConfigFn EndpointTLSConfigFunc,
 	triggers []factory.Informer,
 	recorder events.Recorder,
+	opts ...Option,
 ) factory.Controller {
 	controllerName := name + "EndpointAccessibleController"
 
@@ -46,6 +74,10 @@ func NewEndpointAccessibleController(
 		endpointListFn:         endpointListFn,
 		getTLSConfigFn:         getTLSConfigFn,
 		availableConditionName: name + "EndpointAccessibleControllerAvailable",
+		certHostMismatchReason: "CertHostMismatch",
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
 
 	return factory.New().
@@ -107,6 +139,11 @@ func (c *endpointAccessibleController) sync(ctx context.Context, syncCtx factory
 
 			resp, err := client.Do(req)
 			if err != nil {
+				var hostnameErr x509.HostnameError
+				if errors.As(err, &hostnameErr) {
+					errCh <- &certHostMismatchError{endpoint: endpoint, err: hostnameErr}
+					return
+				}
 				errCh <- humanizeError(err)
 				return
 			}
@@ -121,8 +158,12 @@ func (c *endpointAccessibleController) sync(ctx context.Context, syncCtx factory
 	close(errCh)
 
 	var errors []error
+	certHostMismatches := 0
 	for err := range errCh {
 		errors = append(errors, err)
+		if _, ok := err.(*certHostMismatchError); ok {
+			certHostMismatches++
+		}
 	}
 
 	// if at least one endpoint responded, we are available
@@ -140,10 +181,15 @@ func (c *endpointAccessibleController) sync(ctx context.Context, syncCtx factory
 		if len(endpoints) == 0 {
 			errors = append(errors, fmt.Errorf("failed to get oauth-openshift endpoints"))
 		}
+		reason := "EndpointUnavailable"
+		if certHostMismatches > 0 && certHostMismatches == len(errors) {
+			// the certificates are trusted, they just don't match the host
+			reason = c.certHostMismatchReason
+		}
 		if _, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(operatorv1.OperatorCondition{
 			Type:    c.availableConditionName,
 			Status:  operatorv1.ConditionFalse,
-			Reason:  "EndpointUnavailable",
+			Reason:  reason,
 			Message: utilerrors.NewAggregate(errors).Error(),
 		})); err != nil {
 			// append the error to be degraded
@@ -2,9 +2,16 @@ package endpointaccessible
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
@@ -58,3 +65,55 @@ func Test_endpointAccessibleController_sync(t *testing.T) {
 		})
 	}
 }
+
+func Test_endpointAccessibleController_certHostMismatch(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		expectedStatus operatorv1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "certificate covers the host",
+			endpoint:       server.URL,
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "AsExpected",
+		},
+		{
+			// the test certificate is only valid for example.com and the loopback IPs
+			name:           "certificate does not cover the host",
+			endpoint:       "https://localhost:" + port,
+			expectedStatus: operatorv1.ConditionFalse,
+			expectedReason: "RouteCertHostMismatch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			c := &endpointAccessibleController{
+				operatorClient:         operatorClient,
+				endpointListFn:         func() ([]string, error) { return []string{tt.endpoint}, nil },
+				getTLSConfigFn:         func() (*tls.Config, error) { return &tls.Config{RootCAs: rootCAs}, nil },
+				availableConditionName: "TestAvailable",
+			}
+			WithCertHostMismatchReason("RouteCertHostMismatch")(c)
+
+			_ = c.sync(context.Background(), factory.NewSyncContext(tt.name, events.NewInMemoryRecorder(tt.name)))
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			require.NoError(t, err)
+			condition := v1helpers.FindOperatorCondition(status.Conditions, "TestAvailable")
+			require.NotNil(t, condition)
+			require.Equal(t, tt.expectedStatus, condition.Status, condition.Message)
+			require.Equal(t, tt.expectedReason, condition.Reason)
+		})
+	}
+}
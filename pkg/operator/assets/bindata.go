@@ -16,6 +16,7 @@
 // bindata/oauth-openshift/deployment.yaml
 // bindata/oauth-openshift/ns.yaml
 // bindata/oauth-openshift/oauth-service.yaml
+// bindata/oauth-openshift/prometheusrule.yaml
 // bindata/oauth-openshift/route.yaml
 // bindata/oauth-openshift/serviceaccount.yaml
 // bindata/oauth-openshift/trust_distribution_role.yaml
@@ -630,8 +631,8 @@ spec:
   strategy:
     type: RollingUpdate
     rollingUpdate:
-      maxUnavailable: 1
-      maxSurge: 0
+      maxUnavailable: 0
+      maxSurge: 1
   selector:
     matchLabels:
       app: oauth-openshift
@@ -646,6 +647,8 @@ spec:
     spec:
       terminationGracePeriodSeconds: 40
       serviceAccountName: oauth-openshift
+      # the token is projected into the oauth-server container only, see the kube-api-access volume
+      automountServiceAccountToken: false
       nodeSelector:
         node-role.kubernetes.io/master: ''
       priorityClassName: system-cluster-critical
@@ -662,6 +665,9 @@ spec:
         - key: node-role.kubernetes.io/master
           operator: Exists
           effect: NoSchedule
+        - key: node-role.kubernetes.io/control-plane
+          operator: Exists
+          effect: NoSchedule
         - key: node.kubernetes.io/unreachable
           operator: Exists
           effect: NoExecute
@@ -717,6 +723,9 @@ spec:
             - name: v4-0-config-system-ocp-branding-template
               readOnly: true
               mountPath: /var/config/system/secrets/v4-0-config-system-ocp-branding-template
+            - name: v4-0-config-system-login-banner-template
+              readOnly: true
+              mountPath: /var/config/system/secrets/v4-0-config-system-login-banner-template
             - name: v4-0-config-user-template-login
               readOnly: true
               mountPath: /var/config/user/template/secret/v4-0-config-user-template-login
@@ -729,6 +738,9 @@ spec:
             - name: v4-0-config-system-trusted-ca-bundle
               readOnly: true
               mountPath: /var/config/system/configmaps/v4-0-config-system-trusted-ca-bundle
+            - name: kube-api-access
+              readOnly: true
+              mountPath: /var/run/secrets/kubernetes.io/serviceaccount
           readinessProbe:
             httpGet:
               path: /healthz
@@ -738,6 +750,16 @@ spec:
             periodSeconds: 10
             successThreshold: 1
             failureThreshold: 3
+          # gives a slow starting server up to 5 minutes before the liveness probe kicks in
+          startupProbe:
+            httpGet:
+              path: /healthz
+              port: 6443
+              scheme: HTTPS
+            timeoutSeconds: 1
+            periodSeconds: 10
+            successThreshold: 1
+            failureThreshold: 30
           livenessProbe:
             httpGet:
               path: /healthz
@@ -773,6 +795,7 @@ spec:
         - name: v4-0-config-system-session
           secret:
             secretName: v4-0-config-system-session
+            defaultMode: 0440 # the session signing keys must not be world-readable
         - name: v4-0-config-system-cliconfig
           configMap:
             name: v4-0-config-system-cliconfig
@@ -788,6 +811,10 @@ spec:
         - name: v4-0-config-system-ocp-branding-template
           secret:
             secretName: v4-0-config-system-ocp-branding-template
+        - name: v4-0-config-system-login-banner-template
+          secret:
+            secretName: v4-0-config-system-login-banner-template
+            optional: true
         - name: v4-0-config-user-template-login
           secret:
             secretName: v4-0-config-user-template-login
@@ -804,6 +831,30 @@ spec:
           configMap:
             name: v4-0-config-system-trusted-ca-bundle
             optional: true
+        # the same content as the automounted token, with the token bound to the pod
+        - name: kube-api-access
+          projected:
+            defaultMode: 0420
+            sources:
+              - serviceAccountToken:
+                  expirationSeconds: 3607
+                  path: token
+              - configMap:
+                  name: kube-root-ca.crt
+                  items:
+                    - key: ca.crt
+                      path: ca.crt
+              - downwardAPI:
+                  items:
+                    - path: namespace
+                      fieldRef:
+                        apiVersion: v1
+                        fieldPath: metadata.namespace
+              - configMap:
+                  name: openshift-service-ca.crt
+                  items:
+                    - key: service-ca.crt
+                      path: service-ca.crt
 `)
 
 func oauthOpenshiftDeploymentYamlBytes() ([]byte, error) {
@@ -886,6 +937,44 @@ func oauthOpenshiftOauthServiceYaml() (*asset, error) {
 	return a, nil
 }
 
+var _oauthOpenshiftPrometheusruleYaml = []byte(`apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: authentication-operator
+  namespace: openshift-authentication-operator
+spec:
+  groups:
+    - name: authentication-operator
+      rules:
+        - alert: AuthenticationOperatorControllerStuck
+          annotations:
+            summary: An authentication operator controller stopped reconciling.
+            description: >-
+              The authentication operator controller named in the controller label has not completed a sync for
+              more than 15 minutes although it resyncs every few minutes, the operator may be wedged while still
+              holding its lease. Inspect the operator logs and consider restarting the operator pod.
+          expr: |
+            time() - max by (controller) (openshift_authentication_operator_controller_last_sync_timestamp_seconds) > 900
+          for: 5m
+          labels:
+            severity: warning
+`)
+
+func oauthOpenshiftPrometheusruleYamlBytes() ([]byte, error) {
+	return _oauthOpenshiftPrometheusruleYaml, nil
+}
+
+func oauthOpenshiftPrometheusruleYaml() (*asset, error) {
+	bytes, err := oauthOpenshiftPrometheusruleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "oauth-openshift/prometheusrule.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _oauthOpenshiftRouteYaml = []byte(`# emulates server-side defaulting as in https://github.com/openshift/openshift-apiserver/blob/master/pkg/route/apis/route/configv1listers/defaults.go
 # TODO: replace with server-side apply
 apiVersion: route.openshift.io/v1
@@ -1079,6 +1168,7 @@ var _bindata = map[string]func() (*asset, error){
 	"oauth-openshift/deployment.yaml":                             oauthOpenshiftDeploymentYaml,
 	"oauth-openshift/ns.yaml":                                     oauthOpenshiftNsYaml,
 	"oauth-openshift/oauth-service.yaml":                          oauthOpenshiftOauthServiceYaml,
+	"oauth-openshift/prometheusrule.yaml":                         oauthOpenshiftPrometheusruleYaml,
 	"oauth-openshift/route.yaml":                                  oauthOpenshiftRouteYaml,
 	"oauth-openshift/serviceaccount.yaml":                         oauthOpenshiftServiceaccountYaml,
 	"oauth-openshift/trust_distribution_role.yaml":                oauthOpenshiftTrust_distribution_roleYaml,
@@ -1147,6 +1237,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"deployment.yaml":                        {oauthOpenshiftDeploymentYaml, map[string]*bintree{}},
 		"ns.yaml":                                {oauthOpenshiftNsYaml, map[string]*bintree{}},
 		"oauth-service.yaml":                     {oauthOpenshiftOauthServiceYaml, map[string]*bintree{}},
+		"prometheusrule.yaml":                    {oauthOpenshiftPrometheusruleYaml, map[string]*bintree{}},
 		"route.yaml":                             {oauthOpenshiftRouteYaml, map[string]*bintree{}},
 		"serviceaccount.yaml":                    {oauthOpenshiftServiceaccountYaml, map[string]*bintree{}},
 		"trust_distribution_role.yaml":           {oauthOpenshiftTrust_distribution_roleYaml, map[string]*bintree{}},
@@ -12,6 +12,8 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 )
 
 type ConfigSyncData struct {
@@ -115,7 +117,9 @@ func (sd *ConfigSyncData) Bytes() ([]byte, error) {
 // fields, and performs additional validation of certificates and keys
 func (sd *ConfigSyncData) Validate(cmLister corelistersv1.ConfigMapLister, secretsLister corelistersv1.SecretLister) []error {
 	errs := []error{}
-	for _, src := range sd.data {
+	// keep the order stable so that the reported errors don't shuffle between syncs
+	for _, dest := range sets.StringKeySet(sd.data).List() {
+		src := sd.data[dest]
 		if src.Type == SecretType {
 			if secretErrs := validateSecret(secretsLister, src); len(secretErrs) > 0 {
 				errs = append(errs, fmt.Errorf("error validating secret openshift-config/%s: %w", src.Name, errors.NewAggregate(secretErrs)))
@@ -155,6 +159,23 @@ func (sd *ConfigSyncData) AddIDPConfigMap(index int, configMapRef configv1.Confi
 	return path.Join(data.MountPath, key)
 }
 
+// IDPPath returns the path of the data added for the field of the index-th IdP,
+// it is empty if nothing was added for that field
+func (sd *ConfigSyncData) IDPPath(index int, field string) string {
+	data, ok := sd.data[getIDPName(index, field)]
+	if !ok {
+		return ""
+	}
+	return path.Join(data.MountPath, data.Key)
+}
+
+// Merge adds all the data of other to the data stored here
+func (sd *ConfigSyncData) Merge(other *ConfigSyncData) {
+	for dest, data := range other.data {
+		sd.data[dest] = data
+	}
+}
+
 // ToVolumesAndMounts converts the synchronization data to Volumes and VoulumeMounts
 // so that these can be added to a container spec
 func (sd *ConfigSyncData) ToVolumesAndMounts() ([]corev1.Volume, []corev1.VolumeMount, error) {
@@ -230,7 +251,7 @@ func SyncConfigOrDie(syncFunc func(dest, src resourcesynccontroller.ResourceLoca
 	}
 	if err := syncFunc(
 		resourcesynccontroller.ResourceLocation{
-			Namespace: "openshift-authentication",
+			Namespace: common.TargetNamespace,
 			Name:      dest,
 		},
 		resourcesynccontroller.ResourceLocation{
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"time"
 
 	certapiv1 "k8s.io/api/certificates/v1"
@@ -18,6 +19,8 @@ import (
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/sets"
 	certinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
@@ -61,20 +64,29 @@ import (
 	"github.com/openshift/library-go/pkg/operator/unsupportedconfigoverridescontroller"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configconsistency"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/configobservation/configobservercontroller"
 	componentroutesecretsync "github.com/openshift/cluster-authentication-operator/pkg/controllers/customroute"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/deployment"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/featurestate"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/heartbeat"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/idphealth"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/ingressnodesavailable"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/ingressstate"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/metadata"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/oauthclientscontroller"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/oauthendpoints"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/pause"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/payload"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/proxyconfig"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/readiness"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/routercerts"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/serviceca"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/statedump"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/statusconsistency"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/trustdistribution"
+	"github.com/openshift/cluster-authentication-operator/pkg/controllers/trustedca"
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/webhookauthenticator"
 	"github.com/openshift/cluster-authentication-operator/pkg/operator/assets"
 	oauthapiconfigobservercontroller "github.com/openshift/cluster-authentication-operator/pkg/operator/configobservation/configobservercontroller"
@@ -126,11 +138,11 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 	kubeInformersForNamespaces := v1helpers.NewKubeInformersForNamespaces(
 		kubeClient,
 		"default",
-		"openshift-authentication",
+		common.TargetNamespace,
 		"openshift-config",
 		"openshift-config-managed",
 		"openshift-oauth-apiserver",
-		"openshift-authentication-operator",
+		common.OperatorNamespace,
 		"", // an informer for non-namespaced resources
 		"kube-system",
 		libgoetcd.EtcdEndpointNamespace,
@@ -216,11 +228,11 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		return err
 	}
 
-	openshiftAuthenticationInformers := operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication")
+	openshiftAuthenticationInformers := operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace)
 	kubeSystemNamespaceInformers := operatorCtx.kubeInformersForNamespaces.InformersFor("kube-system")
 
 	routeInformersNamespaced := routeinformer.NewSharedInformerFactoryWithOptions(routeClient, resync,
-		routeinformer.WithNamespace("openshift-authentication"),
+		routeinformer.WithNamespace(common.TargetNamespace),
 		routeinformer.WithTweakListOptions(singleNameListOptions("oauth-openshift")),
 	)
 
@@ -239,7 +251,7 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 	// add syncing for the OAuth metadata ConfigMap
 	if err := operatorCtx.resourceSyncController.SyncConfigMap(
 		resourcesynccontroller.ResourceLocation{Namespace: "openshift-config-managed", Name: "oauth-openshift"},
-		resourcesynccontroller.ResourceLocation{Namespace: "openshift-authentication", Name: "v4-0-config-system-metadata"},
+		resourcesynccontroller.ResourceLocation{Namespace: common.TargetNamespace, Name: "v4-0-config-system-metadata"},
 	); err != nil {
 		return err
 	}
@@ -283,9 +295,14 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 			"oauth-openshift/trust_distribution_role.yaml",
 			"oauth-openshift/trust_distribution_rolebinding.yaml",
 		},
-		resourceapply.NewKubeClientHolder(operatorCtx.kubeClient),
+		resourceapply.NewKubeClientHolder(operatorCtx.kubeClient).WithDynamicClient(dynamic.NewForConfigOrDie(controllerContext.KubeConfig)),
 		operatorCtx.operatorClient,
 		controllerContext.EventRecorder,
+	).WithConditionalResources(
+		assets.Asset,
+		[]string{"oauth-openshift/prometheusrule.yaml"},
+		prometheusRuleCRDExists(operatorCtx.kubeClient.Discovery()),
+		nil,
 	).AddKubeInformers(operatorCtx.kubeInformersForNamespaces)
 
 	configObserver := configobservercontroller.NewConfigObserver(
@@ -305,7 +322,7 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		openshiftAuthenticationInformers.Core().V1().Secrets(),
 		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-config-managed").Core().V1().Secrets(),
 		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-config-managed").Core().V1().ConfigMaps(),
-		"openshift-authentication",
+		common.TargetNamespace,
 		"v4-0-config-system-router-certs",
 		"v4-0-config-system-custom-router-certs",
 		"oauth-openshift",
@@ -316,7 +333,7 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.operatorClient,
-		"openshift-authentication",
+		common.TargetNamespace,
 		controllerContext.EventRecorder)
 
 	wellKnownReadyController := readiness.NewWellKnownReadyController(
@@ -327,19 +344,25 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		controllerContext.EventRecorder,
 	)
 
+	oauthServerDrainController := readiness.NewOAuthServerDrainController(
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
 	metadataController := metadata.NewMetadataController(
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
 		operatorCtx.operatorConfigInformer,
 		routeInformersNamespaced,
 		operatorCtx.kubeClient.CoreV1(),
-		routeClient.RouteV1().Routes("openshift-authentication"),
+		routeClient.RouteV1().Routes(common.TargetNamespace),
 		operatorCtx.configClient.ConfigV1().Authentications(),
 		operatorCtx.operatorClient,
 		controllerContext.EventRecorder,
 	)
 
 	serviceCAController := serviceca.NewServiceCAController(
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
 		operatorCtx.operatorConfigInformer,
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.operatorClient,
@@ -347,7 +370,8 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 	)
 
 	payloadConfigController := payload.NewPayloadConfigController(
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
+		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-config").Core().V1().Secrets(),
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.operatorClient,
@@ -378,7 +402,8 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		bootstrapauthenticator.NewBootstrapUserDataGetter(operatorCtx.kubeClient.CoreV1(), operatorCtx.kubeClient.CoreV1()),
 		controllerContext.EventRecorder,
 		operatorCtx.versionRecorder,
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
+		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-config").Core().V1().ConfigMaps(),
 	)
 
 	workersAvailableController := ingressnodesavailable.NewIngressNodesAvailableController(
@@ -395,7 +420,7 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 
 	authRouteCheckController := oauthendpoints.NewOAuthRouteCheckController(
 		operatorCtx.operatorClient,
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
 		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-config-managed"),
 		routeInformersNamespaced.Route().V1().Routes(),
 		operatorCtx.operatorConfigInformer.Config().V1().Ingresses(),
@@ -405,24 +430,29 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 
 	authServiceCheckController := oauthendpoints.NewOAuthServiceCheckController(
 		operatorCtx.operatorClient,
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
 		controllerContext.EventRecorder,
 	)
 
 	authServiceEndpointCheckController := oauthendpoints.NewOAuthServiceEndpointsCheckController(
 		operatorCtx.operatorClient,
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.TargetNamespace),
+		controllerContext.EventRecorder,
+	)
+
+	authReachabilityController := oauthendpoints.NewOAuthReachabilityController(
+		operatorCtx.operatorClient,
 		controllerContext.EventRecorder,
 	)
 
 	proxyConfigController := proxyconfig.NewProxyConfigChecker(
 		routeInformersNamespaced.Route().V1().Routes(),
 		operatorCtx.kubeInformersForNamespaces,
-		"openshift-authentication",
+		common.TargetNamespace,
 		"oauth-openshift",
 		map[string][]string{
-			"openshift-authentication-operator": {"trusted-ca-bundle"},
-			"openshift-config-managed":          {"default-ingress-cert"},
+			common.OperatorNamespace:   {"trusted-ca-bundle"},
+			"openshift-config-managed": {"default-ingress-cert"},
 		},
 		controllerContext.EventRecorder,
 		operatorCtx.operatorClient,
@@ -431,18 +461,72 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 	customRouteController := componentroutesecretsync.NewCustomRouteController(
 		componentroutesecretsync.OAuthComponentRouteNamespace,
 		componentroutesecretsync.OAuthComponentRouteName,
-		"openshift-authentication",
+		common.TargetNamespace,
 		"v4-0-config-system-custom-router-certs",
 		operatorCtx.operatorConfigInformer.Config().V1().Ingresses(),
 		operatorCtx.configClient.ConfigV1().Ingresses(),
+		operatorCtx.operatorConfigInformer.Config().V1().Infrastructures(),
 		routeInformersNamespaced.Route().V1().Routes(),
-		routeClient.RouteV1().Routes("openshift-authentication"),
+		routeClient.RouteV1().Routes(common.TargetNamespace),
 		operatorCtx.kubeInformersForNamespaces,
 		operatorCtx.operatorClient,
 		controllerContext.EventRecorder,
 		operatorCtx.resourceSyncController,
 	)
 
+	configConsistencyController := configconsistency.NewConfigConsistencyController(
+		operatorCtx.operatorConfigInformer,
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
+	statusConsistencyController := statusconsistency.NewStatusConsistencyController(
+		operatorCtx.operatorConfigInformer,
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
+	idpHealthController := idphealth.NewIdentityProviderHealthController(
+		operatorCtx.kubeInformersForNamespaces,
+		operatorCtx.operatorConfigInformer,
+		operatorCtx.operatorClient,
+		bootstrapauthenticator.NewBootstrapUserDataGetter(operatorCtx.kubeClient.CoreV1(), operatorCtx.kubeClient.CoreV1()),
+		controllerContext.EventRecorder,
+	)
+
+	operatorTrustedCAController := trustedca.NewOperatorTrustedCAController(
+		operatorCtx.kubeClient.CoreV1(),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.OperatorNamespace).Core().V1().ConfigMaps(),
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
+	reconciliationPausedController := pause.NewReconciliationPausedController(
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
+	heartbeatController := heartbeat.NewHeartbeatController(
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
+	stateDumpController := statedump.NewStateDumpController(
+		operatorCtx.kubeInformersForNamespaces,
+		routeInformersNamespaced.Route().V1().Routes(),
+		operatorCtx.kubeClient.CoreV1(),
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
+	featureStateController := featurestate.NewFeatureStateController(
+		operatorCtx.kubeInformersForNamespaces,
+		operatorCtx.operatorConfigInformer.Config().V1().Ingresses(),
+		operatorCtx.kubeClient.CoreV1(),
+		operatorCtx.operatorClient,
+		controllerContext.EventRecorder,
+	)
+
 	// TODO remove this controller once we support Removed
 	managementStateController := managementstatecontroller.NewOperatorManagementStateController("authentication", operatorCtx.operatorClient, controllerContext.EventRecorder)
 	management.SetOperatorNotRemovable()
@@ -451,6 +535,7 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.kubeInformersForNamespaces,
 		operatorCtx.operatorConfigInformer.Config().V1().Ingresses(),
+		operatorCtx.operatorClient,
 		controllerContext.EventRecorder,
 	)
 
@@ -472,13 +557,23 @@ func prepareOauthOperator(ctx context.Context, controllerContext *controllercmd.
 		serviceCAController.Run,
 		staticResourceController.Run,
 		wellKnownReadyController.Run,
+		oauthServerDrainController.Run,
 		authRouteCheckController.Run,
 		authServiceCheckController.Run,
 		authServiceEndpointCheckController.Run,
+		authReachabilityController.Run,
 		workersAvailableController.Run,
 		proxyConfigController.Run,
 		customRouteController.Run,
 		trustDistributionController.Run,
+		reconciliationPausedController.Run,
+		stateDumpController.Run,
+		featureStateController.Run,
+		heartbeatController.Run,
+		operatorTrustedCAController.Run,
+		idpHealthController.Run,
+		configConsistencyController.Run,
+		statusConsistencyController.Run,
 		func(ctx context.Context, workers int) { staleConditions.Run(ctx, workers) },
 		func(ctx context.Context, workers int) { ingressStateController.Run(ctx, workers) },
 	)
@@ -548,7 +643,7 @@ func prepareOauthAPIServerOperator(ctx context.Context, controllerContext *contr
 		eventRecorder,
 	).WithWorkloadController(
 		"OAuthAPIServerController",
-		"openshift-authentication-operator",
+		common.OperatorNamespace,
 		"openshift-oauth-apiserver",
 		os.Getenv("OPERATOR_IMAGE_VERSION"),
 		"oauth",
@@ -670,12 +765,12 @@ func prepareOauthAPIServerOperator(ctx context.Context, controllerContext *contr
 				{Group: configv1.GroupName, Resource: "authentications", Name: "cluster"},
 				{Group: configv1.GroupName, Resource: "infrastructures", Name: "cluster"},
 				{Group: configv1.GroupName, Resource: "oauths", Name: "cluster"},
-				{Group: routev1.GroupName, Resource: "routes", Name: "oauth-openshift", Namespace: "openshift-authentication"},
-				{Resource: "services", Name: "oauth-openshift", Namespace: "openshift-authentication"},
+				{Group: routev1.GroupName, Resource: "routes", Name: "oauth-openshift", Namespace: common.TargetNamespace},
+				{Resource: "services", Name: "oauth-openshift", Namespace: common.TargetNamespace},
 				{Resource: "namespaces", Name: "openshift-config"},
 				{Resource: "namespaces", Name: "openshift-config-managed"},
-				{Resource: "namespaces", Name: "openshift-authentication"},
-				{Resource: "namespaces", Name: "openshift-authentication-operator"},
+				{Resource: "namespaces", Name: common.TargetNamespace},
+				{Resource: "namespaces", Name: common.OperatorNamespace},
 				{Resource: "namespaces", Name: "openshift-ingress"},
 				{Resource: "namespaces", Name: "openshift-oauth-apiserver"},
 			},
@@ -702,7 +797,7 @@ func prepareOauthAPIServerOperator(ctx context.Context, controllerContext *contr
 
 	webhookAuthController := webhookauthenticator.NewWebhookAuthenticatorController(
 		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-oauth-apiserver"),
-		operatorCtx.kubeInformersForNamespaces.InformersFor("openshift-authentication-operator"),
+		operatorCtx.kubeInformersForNamespaces.InformersFor(common.OperatorNamespace),
 		operatorCtx.operatorConfigInformer,
 		operatorCtx.kubeClient.CoreV1(),
 		operatorCtx.kubeClient.CoreV1(),
@@ -750,7 +845,7 @@ func prepareOauthAPIServerOperator(ctx context.Context, controllerContext *contr
 		kubeInformers.Certificates().V1().CertificateSigningRequests(),
 		csr.NewLabelFilter(labelSelector),
 		csr.NewServiceAccountApprover(
-			"openshift-authentication-operator",
+			common.OperatorNamespace,
 			"authentication-operator",
 			"CN=system:serviceaccount:openshift-oauth-apiserver:openshift-authenticator",
 		),
@@ -772,6 +867,47 @@ func prepareOauthAPIServerOperator(ctx context.Context, controllerContext *contr
 	return nil
 }
 
+// prometheusRuleCRDCheckInterval is how long the result of the PrometheusRule CRD
+// discovery is reused, the static resources are synced far more often
+const prometheusRuleCRDCheckInterval = 10 * time.Minute
+
+// prometheusRuleCRDExists tells whether the PrometheusRule CRD is served so that the alerting
+// rules are only applied on clusters that run the monitoring stack. The discovery result is
+// cached for prometheusRuleCRDCheckInterval, failed discoveries are retried on the next call.
+func prometheusRuleCRDExists(discoveryClient discovery.DiscoveryInterface) resourceapply.ConditionalFunction {
+	var (
+		lock      sync.Mutex
+		exists    bool
+		checkedAt time.Time
+	)
+	return func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < prometheusRuleCRDCheckInterval {
+			return exists
+		}
+
+		resources, err := discoveryClient.ServerResourcesForGroupVersion("monitoring.coreos.com/v1")
+		if err != nil && !errors.IsNotFound(err) {
+			klog.Warningf("unable to discover the monitoring.coreos.com/v1 resources: %v", err)
+			return exists
+		}
+
+		exists, checkedAt = false, time.Now()
+		if err != nil {
+			return exists
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == "prometheusrules" {
+				exists = true
+				break
+			}
+		}
+		return exists
+	}
+}
+
 func singleNameListOptions(name string) func(opts *metav1.ListOptions) {
 	return func(opts *metav1.ListOptions) {
 		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()